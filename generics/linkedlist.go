@@ -0,0 +1,59 @@
+package main
+
+type node[T any] struct {
+	value T
+	next  *node[T]
+}
+
+// LinkedList is a singly linked list with O(1) push at both ends.
+type LinkedList[T any] struct {
+	head *node[T]
+	tail *node[T]
+	size int
+}
+
+func (l *LinkedList[T]) PushFront(v T) {
+	n := &node[T]{value: v, next: l.head}
+	l.head = n
+	if l.tail == nil {
+		l.tail = n
+	}
+	l.size++
+}
+
+func (l *LinkedList[T]) PushBack(v T) {
+	n := &node[T]{value: v}
+	if l.tail == nil {
+		l.head = n
+	} else {
+		l.tail.next = n
+	}
+	l.tail = n
+	l.size++
+}
+
+func (l *LinkedList[T]) PopFront() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+	n := l.head
+	l.head = n.next
+	if l.head == nil {
+		l.tail = nil
+	}
+	l.size--
+	return n.value, true
+}
+
+func (l *LinkedList[T]) Len() int {
+	return l.size
+}
+
+func (l *LinkedList[T]) ToSlice() []T {
+	out := make([]T, 0, l.size)
+	for n := l.head; n != nil; n = n.next {
+		out = append(out, n.value)
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedList(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  func(l *LinkedList[int])
+		want []int
+	}{
+		{"empty", func(l *LinkedList[int]) {}, []int{}},
+		{"push back", func(l *LinkedList[int]) { l.PushBack(1); l.PushBack(2) }, []int{1, 2}},
+		{"push front", func(l *LinkedList[int]) { l.PushFront(1); l.PushFront(2) }, []int{2, 1}},
+		{"mixed", func(l *LinkedList[int]) { l.PushBack(2); l.PushFront(1); l.PushBack(3) }, []int{1, 2, 3}},
+		{"pop then push back", func(l *LinkedList[int]) {
+			l.PushBack(1)
+			l.PopFront()
+			l.PushBack(2) // tail must have been cleared by the pop
+		}, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l LinkedList[int]
+			tt.ops(&l)
+			if got := l.ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("ToSlice() = %v, want %v", got, tt.want)
+			}
+			if l.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", l.Len(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestLinkedListPopFront(t *testing.T) {
+	var l LinkedList[string]
+	if v, ok := l.PopFront(); ok {
+		t.Errorf("PopFront() on empty list = %q, true", v)
+	}
+	l.PushBack("a")
+	l.PushBack("b")
+	for _, want := range []string{"a", "b"} {
+		if v, ok := l.PopFront(); !ok || v != want {
+			t.Errorf("PopFront() = %q, %v; want %q", v, ok, want)
+		}
+	}
+	if v, ok := l.PopFront(); ok || l.Len() != 0 {
+		t.Errorf("PopFront() after draining = %q, %v; Len() = %d", v, ok, l.Len())
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "container/list"
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRUCache is a fixed-capacity cache that evicts the least recently used
+// entry. The list keeps entries from most to least recently used.
+type LRUCache[K comparable, V any] struct {
+	capacity int
+	order    *list.List
+	items    map[K]*list.Element
+}
+
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+func (c *LRUCache[K, V]) Get(k K) (V, bool) {
+	el, ok := c.items[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+func (c *LRUCache[K, V]) Put(k K, v V) {
+	if el, ok := c.items[k]; ok {
+		el.Value.(*lruEntry[K, V]).value = v
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[k] = c.order.PushFront(&lruEntry[K, V]{key: k, value: v})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestLRUCache(t *testing.T) {
+	type lookup struct {
+		key  string
+		want int
+		ok   bool
+	}
+	tests := []struct {
+		name     string
+		capacity int
+		ops      func(c *LRUCache[string, int])
+		lookups  []lookup
+	}{
+		{"evicts least recently put", 2, func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+		}, []lookup{{"a", 0, false}, {"b", 2, true}, {"c", 3, true}}},
+		{"get refreshes recency", 2, func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Get("a")
+			c.Put("c", 3)
+		}, []lookup{{"a", 1, true}, {"b", 0, false}, {"c", 3, true}}},
+		{"update refreshes and replaces", 2, func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("a", 10)
+			c.Put("c", 3)
+		}, []lookup{{"a", 10, true}, {"b", 0, false}}},
+		{"missing key", 2, func(c *LRUCache[string, int]) {}, []lookup{{"x", 0, false}}},
+		{"zero capacity keeps nothing", 0, func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+		}, []lookup{{"a", 0, false}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLRUCache[string, int](tt.capacity)
+			tt.ops(c)
+			for _, l := range tt.lookups {
+				if got, ok := c.Get(l.key); got != l.want || ok != l.ok {
+					t.Errorf("Get(%q) = %d, %v; want %d, %v", l.key, got, ok, l.want, l.ok)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,32 @@
 package main
 
 import (
+	"container/heap"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 )
 
 type Ordered interface {
 	~int | ~float64 | ~string
 }
 
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+var (
+	ErrDivideByZero      = errors.New("division by zero")
+	ErrInvalidWindowSize = errors.New("window size must be positive")
+	ErrTimeout           = errors.New("timed out")
+)
+
 func Min[T Ordered](a, b T) T {
 	if a < b {
 		return a
@@ -21,8 +40,612 @@ func Max[T Ordered](a, b T) T {
 	}
 	return b
 }
+
+func SafeDivide[T Number](a, b T) (T, error) {
+	if b == 0 {
+		var zero T
+		return zero, ErrDivideByZero
+	}
+	return a / b, nil
+}
+
+// Abs returns the absolute value of v. For unsigned types it is v itself.
+func Abs[T Number](v T) T {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// ParseNumber parses s into T, choosing the strconv parser from T's kind.
+// Values that overflow T are reported as errors.
+func ParseNumber[T Number](s string) (T, error) {
+	var out T
+	v := reflect.ValueOf(&out).Elem()
+	bits := v.Type().Bits()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, bits)
+		if err != nil {
+			return out, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			return out, err
+		}
+		v.SetUint(n)
+	default:
+		n, err := strconv.ParseFloat(s, bits)
+		if err != nil {
+			return out, err
+		}
+		v.SetFloat(n)
+	}
+	return out, nil
+}
+
+// SortBy sorts s in place by the key returned from keyFn.
+func SortBy[T any, K Ordered](s []T, keyFn func(T) K) {
+	sort.Slice(s, func(i, j int) bool {
+		return keyFn(s[i]) < keyFn(s[j])
+	})
+}
+
+// Partition splits s into the elements that satisfy pred and the rest,
+// preserving the original order in both.
+func Partition[T any](s []T, pred func(T) bool) (matches, rest []T) {
+	for _, v := range s {
+		if pred(v) {
+			matches = append(matches, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matches, rest
+}
+
+// memoEntry computes one key's value exactly once.
+type memoEntry[V any] struct {
+	once sync.Once
+	v    V
+}
+
+// Memoize caches f's result per key. f is called at most once per distinct
+// key, even under concurrent use. The cache lock is not held while f runs,
+// so f may call the memoized function for other keys (e.g. recursive fib)
+// and different keys are computed in parallel. f must not call it for the
+// key it is computing.
+func Memoize[K comparable, V any](f func(K) V) func(K) V {
+	var mu sync.Mutex
+	cache := make(map[K]*memoEntry[V])
+	return func(k K) V {
+		mu.Lock()
+		e, ok := cache[k]
+		if !ok {
+			e = &memoEntry[V]{}
+			cache[k] = e
+		}
+		mu.Unlock()
+		e.once.Do(func() { e.v = f(k) })
+		return e.v
+	}
+}
+
+// Flatten concatenates the inner slices in order. The result is never nil.
+func Flatten[T any](s [][]T) []T {
+	n := 0
+	for _, inner := range s {
+		n += len(inner)
+	}
+	out := make([]T, 0, n)
+	for _, inner := range s {
+		out = append(out, inner...)
+	}
+	return out
+}
+
+// Find returns the first element that satisfies pred.
+func Find[T any](s []T, pred func(T) bool) (T, bool) {
+	if i := FindIndex(s, pred); i >= 0 {
+		return s[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// FindIndex returns the index of the first element that satisfies pred, or -1.
+func FindIndex[T any](s []T, pred func(T) bool) int {
+	for i, v := range s {
+		if pred(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// minHeap is a heap.Interface over Ordered values with the smallest on top.
+type minHeap[T Ordered] []T
+
+func (h minHeap[T]) Len() int           { return len(h) }
+func (h minHeap[T]) Less(i, j int) bool { return h[i] < h[j] }
+func (h minHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap[T]) Push(x any)        { *h = append(*h, x.(T)) }
+func (h *minHeap[T]) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// TopN returns the n largest elements of s in descending order. It keeps a
+// heap of at most n elements, so it does not sort the whole slice.
+func TopN[T Ordered](s []T, n int) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	h := make(minHeap[T], 0, min(n, len(s)))
+	for _, v := range s {
+		if h.Len() < n {
+			heap.Push(&h, v)
+		} else if v > h[0] {
+			h[0] = v
+			heap.Fix(&h, 0)
+		}
+	}
+	out := make([]T, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(&h).(T)
+	}
+	return out
+}
+
+// MapKeys re-keys m with keyFn. When several keys map to the same new key,
+// which value survives is unspecified because map iteration order is random.
+func MapKeys[K1, K2 comparable, V any](m map[K1]V, keyFn func(K1) K2) map[K2]V {
+	out := make(map[K2]V, len(m))
+	for k, v := range m {
+		out[keyFn(k)] = v
+	}
+	return out
+}
+
+func MapValues[K comparable, V1, V2 any](m map[K]V1, valFn func(V1) V2) map[K]V2 {
+	out := make(map[K]V2, len(m))
+	for k, v := range m {
+		out[k] = valFn(v)
+	}
+	return out
+}
+
+// InsertSorted inserts v into the sorted slice s, after any equal elements,
+// and returns the updated slice.
+func InsertSorted[T Ordered](s []T, v T) []T {
+	i := sort.Search(len(s), func(i int) bool { return s[i] > v })
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Reduce folds s from the first element to the last.
+func Reduce[T, U any](s []T, init U, f func(U, T) U) U {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// ReduceRight is like Reduce but folds from the last element to the first.
+func ReduceRight[T, U any](s []T, init U, f func(U, T) U) U {
+	acc := init
+	for i := len(s) - 1; i >= 0; i-- {
+		acc = f(acc, s[i])
+	}
+	return acc
+}
+
+// ParallelMap is like Map but spreads the calls to f over workers goroutines.
+// out[i] is always f(s[i]). workers <= 1 runs serially.
+func ParallelMap[T, U any](s []T, workers int, f func(T) U) []U {
+	if workers <= 1 {
+		return Map(s, f)
+	}
+
+	out := make([]U, len(s))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				out[i] = f(s[i])
+			}
+		}()
+	}
+	for i := range s {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return out
+}
+
+// FanIn merges chans into one channel that is closed after every input is
+// drained.
+func FanIn[T any](chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for v := range ch {
+				out <- v
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee returns n copies of s, each with its own backing array.
+func Tee[T any](s []T, n int) [][]T {
+	if n <= 0 {
+		return [][]T{}
+	}
+	out := make([][]T, n)
+	for i := range out {
+		out[i] = append(make([]T, 0, len(s)), s...)
+	}
+	return out
+}
+
+// Any reports whether some element satisfies pred. It is false for an empty slice.
+func Any[T any](s []T, pred func(T) bool) bool {
+	for _, v := range s {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether every element satisfies pred. It is true for an empty slice.
+func All[T any](s []T, pred func(T) bool) bool {
+	for _, v := range s {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Windows returns every contiguous sub-slice of s with the given size. The
+// windows share s's backing array. A size larger than s yields no windows.
+func Windows[T any](s []T, size int) ([][]T, error) {
+	if size <= 0 {
+		return nil, ErrInvalidWindowSize
+	}
+	if size > len(s) {
+		return [][]T{}, nil
+	}
+	out := make([][]T, 0, len(s)-size+1)
+	for i := 0; i+size <= len(s); i++ {
+		out = append(out, s[i:i+size:i+size])
+	}
+	return out, nil
+}
+
+// ToMap indexes s by keyFn. On a key collision the later element wins.
+func ToMap[T any, K comparable](s []T, keyFn func(T) K) map[K]T {
+	out := make(map[K]T, len(s))
+	for _, v := range s {
+		out[keyFn(v)] = v
+	}
+	return out
+}
+
+// Repeat returns a slice holding v n times.
+func Repeat[T any](v T, n int) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	out := make([]T, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+// Cycle repeats the elements of s in order until the result has n elements.
+// An empty s yields an empty result.
+func Cycle[T any](s []T, n int) []T {
+	if n <= 0 || len(s) == 0 {
+		return []T{}
+	}
+	out := make([]T, n)
+	for i := range out {
+		out[i] = s[i%len(s)]
+	}
+	return out
+}
+
+// UniqueBy keeps the first element for each key returned by keyFn,
+// preserving order.
+func UniqueBy[T any, K comparable](s []T, keyFn func(T) K) []T {
+	seen := make(map[K]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		k := keyFn(v)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Compact returns the elements of s that are not the zero value of T, in
+// order.
+func Compact[T comparable](s []T) []T {
+	var zero T
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if v != zero {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SafeIndex returns s[i], or false instead of panicking when i is out of
+// range.
+func SafeIndex[T any](s []T, i int) (T, bool) {
+	if i < 0 || i >= len(s) {
+		var zero T
+		return zero, false
+	}
+	return s[i], true
+}
+
+// ForEach calls f on every element, even after a failure, and returns all
+// errors joined with errors.Join. It returns nil when every call succeeds.
+func ForEach[T any](s []T, f func(T) error) error {
+	var errs []error
+	for _, v := range s {
+		if err := f(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithTimeout runs f in a goroutine and returns ErrTimeout if it has not
+// finished within d. f is not cancelled; its result is discarded when it
+// completes late.
+func WithTimeout[T any](d time.Duration, f func() (T, error)) (T, error) {
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1) // buffered so a late f does not leak
+	go func() {
+		v, err := f()
+		done <- result{v, err}
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-timer.C:
+		var zero T
+		return zero, fmt.Errorf("after %v: %w", d, ErrTimeout)
+	}
+}
+
+// Coalesce returns the first value that is not the zero value of T, or the
+// zero value if there is none.
+func Coalesce[T comparable](vals ...T) T {
+	var zero T
+	for _, v := range vals {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+type person struct {
+	Name string
+	Age  int
+}
+
 func main() {
 	fmt.Println(Min(3, 7))            // 3
 	fmt.Println(Min(2.5, 1.2))        // 1.2
 	fmt.Println(Min("go", "generic")) // generic
+
+	people := []person{{"Carol", 35}, {"Alice", 30}, {"Bob", 25}}
+	SortBy(people, func(p person) int { return p.Age })
+	fmt.Println(people) // [{Bob 25} {Alice 30} {Carol 35}]
+	SortBy(people, func(p person) string { return p.Name })
+	fmt.Println(people) // [{Alice 30} {Bob 25} {Carol 35}]
+
+	evens, odds := Partition([]int{1, 2, 3, 4, 5}, func(n int) bool { return n%2 == 0 })
+	fmt.Println(evens, odds) // [2 4] [1 3 5]
+
+	list := &LinkedList[int]{}
+	list.PushBack(2)
+	list.PushFront(1)
+	list.PushBack(3)
+	fmt.Println(list.ToSlice(), list.Len()) // [1 2 3] 3
+	first, _ := list.PopFront()
+	fmt.Println(first, list.ToSlice()) // 1 [2 3]
+
+	calls := 0
+	square := Memoize(func(n int) int {
+		calls++
+		return n * n
+	})
+	square(4)
+	square(4)
+	fmt.Println(square(5), calls) // 25 2
+
+	fmt.Println(SafeDivide(10, 4))  // 2 <nil>
+	fmt.Println(SafeDivide(1.0, 0)) // 0 division by zero
+	fmt.Println(Abs(-3), Abs(2.5))  // 3 2.5
+
+	fmt.Println(Flatten([][]int{{1, 2}, {}, {3}})) // [1 2 3]
+	fmt.Println(Flatten([][]int{}) != nil)         // true
+
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")    // "a" is now the most recently used
+	cache.Put("c", 3) // evicts "b"
+	_, hasB := cache.Get("b")
+	a, _ := cache.Get("a")
+	fmt.Println(hasB, a) // false 1
+
+	fmt.Println(Find(people, func(p person) bool { return p.Age > 26 }))           // {Alice 30} true
+	fmt.Println(FindIndex(people, func(p person) bool { return p.Name == "Zoe" })) // -1
+
+	scores := []int{5, 1, 9, 3, 7}
+	fmt.Println(TopN(scores, 2))  // [9 7]
+	fmt.Println(TopN(scores, 5))  // [9 7 5 3 1]
+	fmt.Println(TopN(scores, 10)) // [9 7 5 3 1]
+
+	stock := map[int]int{1: 10, 2: 20}
+	fmt.Println(MapKeys(stock, func(k int) string { return fmt.Sprintf("sku-%d", k) })) // map[sku-1:10 sku-2:20]
+	fmt.Println(MapValues(stock, func(v int) int { return v * 2 }))                     // map[1:20 2:40]
+
+	sorted := InsertSorted([]int{}, 5)
+	sorted = InsertSorted(sorted, 1)
+	sorted = InsertSorted(sorted, 9)
+	sorted = InsertSorted(sorted, 4)
+	fmt.Println(sorted) // [1 4 5 9]
+
+	cube := func(n int) int { return n * n * n }
+	fmt.Println(ParallelMap([]int{1, 2, 3, 4, 5}, 3, cube)) // [1 8 27 64 125]
+
+	produce := func(vals ...int) <-chan int {
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for _, v := range vals {
+				ch <- v
+			}
+		}()
+		return ch
+	}
+	sum := 0
+	for v := range FanIn(produce(1, 2), produce(3), produce(4, 5)) {
+		sum += v
+	}
+	fmt.Println(sum) // 15
+
+	copies := Tee([]int{1, 2, 3}, 2)
+	copies[0][0] = 99
+	fmt.Println(copies) // [[99 2 3] [1 2 3]]
+
+	isAdult := func(p person) bool { return p.Age >= 18 }
+	fmt.Println(Any(people, isAdult), All(people, isAdult))         // true true
+	fmt.Println(Any([]person{}, isAdult), All([]person{}, isAdult)) // false true
+
+	fmt.Println(ParseNumber[int]("42"))       // 42 <nil>
+	fmt.Println(ParseNumber[float64]("3.14")) // 3.14 <nil>
+	fmt.Println(ParseNumber[int]("abc"))      // 0 strconv.ParseInt: parsing "abc": invalid syntax
+
+	fmt.Println(Windows([]int{1, 2, 3, 4}, 2)) // [[1 2] [2 3] [3 4]] <nil>
+	fmt.Println(Windows([]int{1, 2, 3, 4}, 0)) // [] window size must be positive
+
+	minQ := NewPriorityQueue(func(a, b int) bool { return a < b })
+	maxQ := NewPriorityQueue(func(a, b int) bool { return a > b })
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		minQ.Push(v)
+		maxQ.Push(v)
+	}
+	var mins, maxs []int
+	for minQ.Len() > 0 {
+		v, _ := minQ.Pop()
+		mins = append(mins, v)
+		v, _ = maxQ.Pop()
+		maxs = append(maxs, v)
+	}
+	fmt.Println(mins, maxs) // [1 2 3 4 5] [5 4 3 2 1]
+	_, popped := minQ.Pop()
+	fmt.Println(popped) // false
+
+	byName := ToMap(people, func(p person) string { return p.Name })
+	fmt.Println(byName["Bob"]) // {Bob 25}
+	byAge := ToMap([]person{{"Ann", 40}, {"Ben", 40}}, func(p person) int { return p.Age })
+	fmt.Println(len(byAge), byAge[40]) // 1 {Ben 40}
+
+	fmt.Println(Repeat("ab", 3), len(Repeat(1, -1))) // [ab ab ab] 0
+	fmt.Println(Cycle([]int{1, 2, 3}, 7))            // [1 2 3 1 2 3 1]
+	fmt.Println(len(Cycle([]int{}, 5)))              // 0
+
+	concat := func(acc string, s string) string { return acc + s }
+	fmt.Println(Reduce([]string{"a", "b", "c"}, ">", concat), ReduceRight([]string{"a", "b", "c"}, ">", concat)) // >abc >cba
+
+	team := []person{{"Ann", 40}, {"Ben", 40}, {"Cid", 22}, {"Dee", 22}, {"Eve", 31}}
+	fmt.Println(UniqueBy(team, func(p person) int { return p.Age })) // [{Ann 40} {Cid 22} {Eve 31}]
+
+	fmt.Printf("%q %v\n", Compact([]string{"a", "", "b", ""}), Compact([]int{0, 1, 0, 2})) // ["a" "b"] [1 2]
+	fmt.Println(Compact([]int{3, 4}))                                                      // [3 4]
+
+	letters := []string{"x", "y"}
+	letter, okFirst := SafeIndex(letters, 0)
+	_, okNeg := SafeIndex(letters, -1)
+	_, okPast := SafeIndex(letters, 2)
+	fmt.Println(letter, okFirst, okNeg, okPast) // x true false false
+
+	processed := 0
+	forEachErr := ForEach([]int{1, -2, 3, -4}, func(n int) error {
+		processed++
+		if n < 0 {
+			return fmt.Errorf("negative: %d", n)
+		}
+		return nil
+	})
+	fmt.Println(processed, forEachErr) // 4 negative: -2\nnegative: -4
+
+	median := NewRunningMedian[int]()
+	_, hasMedian := median.Median()
+	var medians []float64
+	for _, v := range []int{5, 15, 1, 3} {
+		median.Add(v)
+		m, _ := median.Median()
+		medians = append(medians, m)
+	}
+	fmt.Println(hasMedian, medians) // false [5 10 5 4]
+
+	fast, err := WithTimeout(time.Second, func() (int, error) { return 42, nil })
+	fmt.Println(fast, err) // 42 <nil>
+	_, err = WithTimeout(10*time.Millisecond, func() (int, error) {
+		time.Sleep(100 * time.Millisecond)
+		return 0, nil
+	})
+	fmt.Println(err) // after 10ms: timed out
+
+	fmt.Printf("%q %q %d\n", Coalesce("", "", "fallback"), Coalesce("", ""), Coalesce(0, 8, 9)) // "fallback" "" 8
 }
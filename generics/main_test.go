@@ -0,0 +1,664 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoizeRecursive(t *testing.T) {
+	var calls int
+	var fib func(int) int
+	fib = Memoize(func(n int) int {
+		calls++
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	})
+
+	done := make(chan int)
+	go func() { done <- fib(50) }()
+	select {
+	case got := <-done:
+		if got != 12586269025 {
+			t.Errorf("fib(50) = %d, want 12586269025", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("recursive Memoize deadlocked")
+	}
+	if calls != 51 {
+		t.Errorf("f called %d times, want 51", calls)
+	}
+}
+
+func TestMemoizeConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	slow := Memoize(func(k string) int {
+		calls.Add(1)
+		if k == "slow" {
+			<-release
+		}
+		return len(k)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := slow("slow"); got != 4 {
+				t.Errorf(`slow("slow") = %d, want 4`, got)
+			}
+		}()
+	}
+
+	// A different key must not wait for "slow" to finish.
+	fast := make(chan int)
+	go func() { fast <- slow("fast") }()
+	select {
+	case got := <-fast:
+		if got != 4 {
+			t.Errorf(`slow("fast") = %d, want 4`, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a different key blocked behind a running computation")
+	}
+
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 2 {
+		t.Errorf("f called %d times, want 2", got)
+	}
+}
+
+func TestAbs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   int
+		want int
+	}{
+		{"negative", -7, 7},
+		{"positive", 5, 5},
+		{"zero", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Abs(tt.in); got != tt.want {
+				t.Errorf("Abs(%d) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+	if got := Abs(-2.5); got != 2.5 {
+		t.Errorf("Abs(-2.5) = %v", got)
+	}
+	if got := Abs(uint(3)); got != 3 {
+		t.Errorf("Abs(uint(3)) = %d", got)
+	}
+}
+
+func TestSafeDivide(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    int
+		want    int
+		wantErr error
+	}{
+		{"exact", 10, 2, 5, nil},
+		{"truncates", 7, 2, 3, nil},
+		{"negative", -9, 3, -3, nil},
+		{"by zero", 1, 0, 0, ErrDivideByZero},
+		{"zero by zero", 0, 0, 0, ErrDivideByZero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SafeDivide(tt.a, tt.b)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("SafeDivide(%d, %d) = %d, %v; want %d, %v", tt.a, tt.b, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+	if got, err := SafeDivide(1.0, 4.0); got != 0.25 || err != nil {
+		t.Errorf("SafeDivide(1.0, 4.0) = %v, %v", got, err)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	t.Run("int8", func(t *testing.T) {
+		tests := []struct {
+			in      string
+			want    int8
+			wantErr bool
+		}{
+			{"42", 42, false},
+			{"-128", -128, false},
+			{"128", 0, true}, // overflows int8
+			{"4.2", 0, true},
+			{"", 0, true},
+		}
+		for _, tt := range tests {
+			got, err := ParseNumber[int8](tt.in)
+			if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+				t.Errorf("ParseNumber[int8](%q) = %d, %v", tt.in, got, err)
+			}
+		}
+	})
+	t.Run("uint", func(t *testing.T) {
+		if got, err := ParseNumber[uint]("7"); got != 7 || err != nil {
+			t.Errorf(`ParseNumber[uint]("7") = %d, %v`, got, err)
+		}
+		if _, err := ParseNumber[uint]("-1"); err == nil {
+			t.Error(`ParseNumber[uint]("-1") succeeded`)
+		}
+	})
+	t.Run("float32", func(t *testing.T) {
+		if got, err := ParseNumber[float32]("1.5"); got != 1.5 || err != nil {
+			t.Errorf(`ParseNumber[float32]("1.5") = %v, %v`, got, err)
+		}
+		if _, err := ParseNumber[float32]("1e40"); err == nil {
+			t.Error(`ParseNumber[float32]("1e40") succeeded`)
+		}
+	})
+}
+
+func TestSortBy(t *testing.T) {
+	people := []person{{"Carol", 35}, {"Alice", 30}, {"Bob", 25}}
+	SortBy(people, func(p person) int { return p.Age })
+	if want := []person{{"Bob", 25}, {"Alice", 30}, {"Carol", 35}}; !slices.Equal(people, want) {
+		t.Errorf("SortBy age = %v, want %v", people, want)
+	}
+	SortBy(people, func(p person) string { return p.Name })
+	if want := []person{{"Alice", 30}, {"Bob", 25}, {"Carol", 35}}; !slices.Equal(people, want) {
+		t.Errorf("SortBy name = %v, want %v", people, want)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         []int
+		even, odds []int
+	}{
+		{"mixed", []int{1, 2, 3, 4, 5}, []int{2, 4}, []int{1, 3, 5}},
+		{"all match", []int{2, 4}, []int{2, 4}, nil},
+		{"none match", []int{1, 3}, nil, []int{1, 3}},
+		{"empty", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			even, odds := Partition(tt.in, func(n int) bool { return n%2 == 0 })
+			if !slices.Equal(even, tt.even) || !slices.Equal(odds, tt.odds) {
+				t.Errorf("Partition = %v, %v; want %v, %v", even, odds, tt.even, tt.odds)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name string
+		in   [][]int
+		want []int
+	}{
+		{"nested", [][]int{{1, 2}, {}, {3}}, []int{1, 2, 3}},
+		{"nil inner", [][]int{nil, {4}}, []int{4}},
+		{"empty", nil, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flatten(tt.in)
+			if got == nil || !slices.Equal(got, tt.want) {
+				t.Errorf("Flatten = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     []int
+		target int
+		index  int
+	}{
+		{"first of several", []int{1, 5, 3, 5}, 5, 1},
+		{"missing", []int{1, 2}, 9, -1},
+		{"empty", nil, 1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred := func(n int) bool { return n == tt.target }
+			if got := FindIndex(tt.in, pred); got != tt.index {
+				t.Errorf("FindIndex = %d, want %d", got, tt.index)
+			}
+			v, ok := Find(tt.in, pred)
+			if ok != (tt.index >= 0) || (ok && v != tt.target) {
+				t.Errorf("Find = %d, %v", v, ok)
+			}
+		})
+	}
+}
+
+func TestTopN(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		n    int
+		want []int
+	}{
+		{"top three", []int{5, 1, 9, 3, 7}, 3, []int{9, 7, 5}},
+		{"duplicates", []int{4, 4, 1, 4}, 2, []int{4, 4}},
+		{"n larger than input", []int{2, 1}, 5, []int{2, 1}},
+		{"n zero", []int{1}, 0, []int{}},
+		{"n negative", []int{1}, -1, []int{}},
+		{"empty", nil, 2, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TopN(tt.in, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("TopN(%v, %d) = %v, want %v", tt.in, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapKeysAndValues(t *testing.T) {
+	m := map[string]int{"a": 1, "bb": 2}
+	keys := MapKeys(m, strings.ToUpper)
+	if !maps.Equal(keys, map[string]int{"A": 1, "BB": 2}) {
+		t.Errorf("MapKeys = %v", keys)
+	}
+	byLen := MapKeys(m, func(k string) int { return len(k) })
+	if !maps.Equal(byLen, map[int]int{1: 1, 2: 2}) {
+		t.Errorf("MapKeys by len = %v", byLen)
+	}
+	doubled := MapValues(m, func(v int) int { return v * 2 })
+	if !maps.Equal(doubled, map[string]int{"a": 2, "bb": 4}) {
+		t.Errorf("MapValues = %v", doubled)
+	}
+	if got := MapKeys(map[string]int{}, strings.ToUpper); got == nil || len(got) != 0 {
+		t.Errorf("MapKeys of empty map = %#v", got)
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		v    int
+		want []int
+	}{
+		{"middle", []int{1, 3, 5}, 4, []int{1, 3, 4, 5}},
+		{"front", []int{2, 3}, 1, []int{1, 2, 3}},
+		{"back", []int{2, 3}, 9, []int{2, 3, 9}},
+		{"after equal", []int{1, 2, 2, 3}, 2, []int{1, 2, 2, 2, 3}},
+		{"empty", nil, 7, []int{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsertSorted(slices.Clone(tt.in), tt.v); !slices.Equal(got, tt.want) {
+				t.Errorf("InsertSorted(%v, %d) = %v, want %v", tt.in, tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	concat := func(acc string, s string) string { return acc + s }
+	tests := []struct {
+		name        string
+		in          []string
+		left, right string
+	}{
+		{"several", []string{"a", "b", "c"}, "abc", "cba"},
+		{"one", []string{"a"}, "a", "a"},
+		{"empty", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reduce(tt.in, "", concat); got != tt.left {
+				t.Errorf("Reduce = %q, want %q", got, tt.left)
+			}
+			if got := ReduceRight(tt.in, "", concat); got != tt.right {
+				t.Errorf("ReduceRight = %q, want %q", got, tt.right)
+			}
+		})
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	want := Map(in, func(n int) int { return n * n })
+	for _, workers := range []int{-1, 0, 1, 4, 200} {
+		t.Run(fmt.Sprint("workers=", workers), func(t *testing.T) {
+			var running, peak atomic.Int32
+			got := ParallelMap(in, workers, func(n int) int {
+				cur := running.Add(1)
+				for {
+					p := peak.Load()
+					if cur <= p || peak.CompareAndSwap(p, cur) {
+						break
+					}
+				}
+				defer running.Add(-1)
+				return n * n
+			})
+			if !slices.Equal(got, want) {
+				t.Errorf("ParallelMap = %v", got)
+			}
+			if limit := max(workers, 1); int(peak.Load()) > limit {
+				t.Errorf("%d calls ran at once, want at most %d", peak.Load(), limit)
+			}
+		})
+	}
+	if got := ParallelMap(nil, 4, func(n int) int { return n }); len(got) != 0 {
+		t.Errorf("ParallelMap(nil) = %v", got)
+	}
+}
+
+func TestFanIn(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs [][]int
+	}{
+		{"several", [][]int{{1, 2, 3}, {4, 5}, {6}}},
+		{"one empty", [][]int{{}, {7, 8}}},
+		{"no inputs", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chans := make([]<-chan int, len(tt.inputs))
+			for i, vals := range tt.inputs {
+				ch := make(chan int)
+				chans[i] = ch
+				go func(vals []int) {
+					defer close(ch)
+					for _, v := range vals {
+						ch <- v
+					}
+				}(vals)
+			}
+			var got []int
+			for v := range FanIn(chans...) {
+				got = append(got, v)
+			}
+			want := Flatten(tt.inputs)
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("FanIn = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTee(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		n    int
+	}{
+		{"three copies", []int{1, 2}, 3},
+		{"empty input", nil, 2},
+		{"zero copies", []int{1}, 0},
+		{"negative copies", []int{1}, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copies := Tee(tt.in, tt.n)
+			if len(copies) != max(tt.n, 0) {
+				t.Fatalf("got %d copies, want %d", len(copies), max(tt.n, 0))
+			}
+			for i, c := range copies {
+				if !slices.Equal(c, tt.in) {
+					t.Errorf("copy %d = %v, want %v", i, c, tt.in)
+				}
+			}
+			if len(copies) > 1 && len(tt.in) > 0 {
+				copies[0][0] = -1
+				if copies[1][0] == -1 || tt.in[0] == -1 {
+					t.Error("copies share a backing array")
+				}
+			}
+		})
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	positive := func(n int) bool { return n > 0 }
+	tests := []struct {
+		name     string
+		in       []int
+		any, all bool
+	}{
+		{"all positive", []int{1, 2}, true, true},
+		{"some positive", []int{-1, 2}, true, false},
+		{"none positive", []int{-1, 0}, false, false},
+		{"empty", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Any(tt.in, positive); got != tt.any {
+				t.Errorf("Any = %v, want %v", got, tt.any)
+			}
+			if got := All(tt.in, positive); got != tt.all {
+				t.Errorf("All = %v, want %v", got, tt.all)
+			}
+		})
+	}
+}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []int
+		size    int
+		want    [][]int
+		wantErr error
+	}{
+		{"pairs", []int{1, 2, 3}, 2, [][]int{{1, 2}, {2, 3}}, nil},
+		{"whole slice", []int{1, 2}, 2, [][]int{{1, 2}}, nil},
+		{"larger than slice", []int{1}, 2, [][]int{}, nil},
+		{"zero size", []int{1}, 0, nil, ErrInvalidWindowSize},
+		{"negative size", []int{1}, -1, nil, ErrInvalidWindowSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Windows(tt.in, tt.size)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]int]) {
+				t.Errorf("Windows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Appending to a window must not overwrite the next element of s.
+	s := []int{1, 2, 3}
+	w, _ := Windows(s, 2)
+	_ = append(w[0], 99)
+	if s[2] != 3 {
+		t.Errorf("append to a window changed s to %v", s)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	people := []person{{"Alice", 30}, {"Bob", 25}, {"Alice", 31}}
+	got := ToMap(people, func(p person) string { return p.Name })
+	want := map[string]person{"Alice": {"Alice", 31}, "Bob": {"Bob", 25}}
+	if !maps.Equal(got, want) {
+		t.Errorf("ToMap = %v, want %v", got, want)
+	}
+}
+
+func TestRepeatAndCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"repeat", Repeat("x", 3), []string{"x", "x", "x"}},
+		{"repeat zero", Repeat("x", 0), []string{}},
+		{"repeat negative", Repeat("x", -1), []string{}},
+		{"cycle wraps", Cycle([]string{"a", "b"}, 5), []string{"a", "b", "a", "b", "a"}},
+		{"cycle shorter", Cycle([]string{"a", "b", "c"}, 2), []string{"a", "b"}},
+		{"cycle empty input", Cycle([]string{}, 3), []string{}},
+		{"cycle zero", Cycle([]string{"a"}, 0), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got == nil || !slices.Equal(tt.got, tt.want) {
+				t.Errorf("got %#v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueBy(t *testing.T) {
+	people := []person{{"Alice", 30}, {"Bob", 25}, {"Alice", 31}, {"Carol", 35}, {"Bob", 26}}
+	got := UniqueBy(people, func(p person) string { return p.Name })
+	if want := []person{{"Alice", 30}, {"Bob", 25}, {"Carol", 35}}; !slices.Equal(got, want) {
+		t.Errorf("UniqueBy = %v, want %v", got, want)
+	}
+	if got := UniqueBy([]int(nil), func(n int) int { return n }); len(got) != 0 {
+		t.Errorf("UniqueBy(nil) = %v", got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"mixed", []string{"a", "", "b", ""}, []string{"a", "b"}},
+		{"all zero", []string{"", ""}, []string{}},
+		{"none zero", []string{"a"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compact(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("Compact = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := Compact([]int{0, 3, 0, 4}); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Compact(ints) = %v, want [3 4]", got)
+	}
+}
+
+func TestSafeIndex(t *testing.T) {
+	s := []string{"a", "b"}
+	tests := []struct {
+		i    int
+		want string
+		ok   bool
+	}{
+		{0, "a", true},
+		{1, "b", true},
+		{2, "", false},
+		{-1, "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := SafeIndex(s, tt.i); got != tt.want || ok != tt.ok {
+			t.Errorf("SafeIndex(%d) = %q, %v; want %q, %v", tt.i, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := SafeIndex([]int(nil), 0); ok {
+		t.Error("SafeIndex(nil, 0) reported ok")
+	}
+}
+
+func TestForEach(t *testing.T) {
+	errOdd := errors.New("odd")
+	tests := []struct {
+		name      string
+		in        []int
+		wantCalls int
+		wantErrs  int
+	}{
+		{"all succeed", []int{2, 4}, 2, 0},
+		{"keeps going after failure", []int{1, 2, 3}, 3, 2},
+		{"empty", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := ForEach(tt.in, func(n int) error {
+				calls++
+				if n%2 != 0 {
+					return fmt.Errorf("%d: %w", n, errOdd)
+				}
+				return nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("f called %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErrs == 0 {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, errOdd) {
+				t.Fatalf("err = %v, want %v", err, errOdd)
+			}
+			if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != tt.wantErrs {
+				t.Errorf("joined %d errors, want %d", got, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		f       func() (int, error)
+		want    int
+		wantErr error
+	}{
+		{"fast", func() (int, error) { return 42, nil }, 42, nil},
+		{"fast error", func() (int, error) { return 0, errFailed }, 0, errFailed},
+		{"slow", func() (int, error) { time.Sleep(time.Second); return 42, nil }, 0, ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WithTimeout(50*time.Millisecond, tt.f)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("WithTimeout = %d, %v; want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{"first set", []string{"a", "b"}, "a"},
+		{"skips zero", []string{"", "", "c"}, "c"},
+		{"all zero", []string{"", ""}, ""},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Coalesce(tt.in...); got != tt.want {
+				t.Errorf("Coalesce(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+// RunningMedian tracks the median of a stream. The smaller half of the
+// values lives in a max-heap and the larger half in a min-heap, so Add is
+// O(log n) and Median is O(1).
+type RunningMedian[T Number] struct {
+	low  *PriorityQueue[T] // max-heap, holds the extra value when odd
+	high *PriorityQueue[T] // min-heap
+}
+
+func NewRunningMedian[T Number]() *RunningMedian[T] {
+	return &RunningMedian[T]{
+		low:  NewPriorityQueue(func(a, b T) bool { return a > b }),
+		high: NewPriorityQueue(func(a, b T) bool { return a < b }),
+	}
+}
+
+func (m *RunningMedian[T]) Add(v T) {
+	if top, ok := m.low.Peek(); !ok || v <= top {
+		m.low.Push(v)
+	} else {
+		m.high.Push(v)
+	}
+	// Rebalance so that low has the same number of values as high, or one more.
+	if m.low.Len() > m.high.Len()+1 {
+		top, _ := m.low.Pop()
+		m.high.Push(top)
+	} else if m.high.Len() > m.low.Len() {
+		top, _ := m.high.Pop()
+		m.low.Push(top)
+	}
+}
+
+// Median returns false until a value has been added.
+func (m *RunningMedian[T]) Median() (float64, bool) {
+	lo, ok := m.low.Peek()
+	if !ok {
+		return 0, false
+	}
+	if m.low.Len() > m.high.Len() {
+		return float64(lo), true
+	}
+	hi, _ := m.high.Peek()
+	return (float64(lo) + float64(hi)) / 2, true
+}
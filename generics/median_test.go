@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestRunningMedian(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []float64 // median after each Add
+	}{
+		{"ascending", []int{1, 2, 3, 4}, []float64{1, 1.5, 2, 2.5}},
+		{"descending", []int{4, 3, 2, 1}, []float64{4, 3.5, 3, 2.5}},
+		{"mixed", []int{5, 15, 1, 3, 8}, []float64{5, 10, 5, 4, 5}},
+		{"duplicates", []int{2, 2, 2}, []float64{2, 2, 2}},
+		{"negative", []int{-3, -1, -2}, []float64{-3, -2, -2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewRunningMedian[int]()
+			for i, v := range tt.in {
+				m.Add(v)
+				if got, ok := m.Median(); !ok || got != tt.want[i] {
+					t.Errorf("after %v: Median() = %v, %v; want %v", tt.in[:i+1], got, ok, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunningMedianEmpty(t *testing.T) {
+	if got, ok := NewRunningMedian[float64]().Median(); ok {
+		t.Errorf("Median() on empty stream = %v, true", got)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import "container/heap"
+
+// pqItems adapts a slice and a less func to heap.Interface.
+type pqItems[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *pqItems[T]) Len() int           { return len(h.items) }
+func (h *pqItems[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *pqItems[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqItems[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+func (h *pqItems[T]) Pop() any {
+	n := len(h.items)
+	v := h.items[n-1]
+	var zero T
+	h.items[n-1] = zero
+	h.items = h.items[:n-1]
+	return v
+}
+
+// PriorityQueue pops elements in the order defined by less: the element for
+// which less reports true against every other comes out first.
+type PriorityQueue[T any] struct {
+	h *pqItems[T]
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: &pqItems[T]{less: less}}
+}
+
+func (q *PriorityQueue[T]) Push(v T) {
+	heap.Push(q.h, v)
+}
+
+// Pop removes and returns the highest-priority element, or false when the
+// queue is empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(q.h).(T), true
+}
+
+// Peek returns the highest-priority element without removing it, or false
+// when the queue is empty.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.h.items[0], true
+}
+
+func (q *PriorityQueue[T]) Len() int {
+	return q.h.Len()
+}
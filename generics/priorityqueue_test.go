@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	tests := []struct {
+		name string
+		less func(a, b int) bool
+		in   []int
+		want []int
+	}{
+		{"min", func(a, b int) bool { return a < b }, []int{5, 1, 4, 1, 3}, []int{1, 1, 3, 4, 5}},
+		{"max", func(a, b int) bool { return a > b }, []int{5, 1, 4, 1, 3}, []int{5, 4, 3, 1, 1}},
+		{"single", func(a, b int) bool { return a < b }, []int{7}, []int{7}},
+		{"empty", func(a, b int) bool { return a < b }, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewPriorityQueue(tt.less)
+			for _, v := range tt.in {
+				q.Push(v)
+			}
+			var got []int
+			for q.Len() > 0 {
+				top, _ := q.Peek()
+				v, ok := q.Pop()
+				if !ok || v != top {
+					t.Fatalf("Pop() = %d, %v; Peek() said %d", v, ok, top)
+				}
+				got = append(got, v)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("popped %v, want %v", got, tt.want)
+			}
+			if v, ok := q.Peek(); ok {
+				t.Errorf("Peek() on empty queue = %d, true", v)
+			}
+			if v, ok := q.Pop(); ok {
+				t.Errorf("Pop() on empty queue = %d, true", v)
+			}
+		})
+	}
+}
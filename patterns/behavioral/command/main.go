@@ -1,11 +1,23 @@
 package main
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
 
 // Command Interface
 type Command interface {
 	Execute()
 	Undo()
+	Name() string
+	CanExecute() bool
 }
 
 // Concrete Commands
@@ -21,6 +33,14 @@ func (c *LightOnCommand) Undo() {
 	c.light.TurnOff()
 }
 
+func (c *LightOnCommand) Name() string {
+	return "light_on"
+}
+
+func (c *LightOnCommand) CanExecute() bool {
+	return c.light.GetStatus() != "ON"
+}
+
 type LightOffCommand struct {
 	light *Light
 }
@@ -33,51 +53,443 @@ func (c *LightOffCommand) Undo() {
 	c.light.TurnOn()
 }
 
+func (c *LightOffCommand) Name() string {
+	return "light_off"
+}
+
+func (c *LightOffCommand) CanExecute() bool {
+	return c.light.GetStatus() != "OFF"
+}
+
+// State Interface
+type LightState interface {
+	PressSwitch(l *Light)
+	Status() string
+}
+
+// Concrete States
+type OnState struct{}
+
+func (s OnState) PressSwitch(l *Light) {
+	l.TurnOff()
+}
+
+func (s OnState) Status() string {
+	return "ON"
+}
+
+type OffState struct{}
+
+func (s OffState) PressSwitch(l *Light) {
+	l.TurnOn()
+}
+
+func (s OffState) Status() string {
+	return "OFF"
+}
+
 // Receiver
 type Light struct {
-	isOn bool
+	state LightState
+}
+
+// currentState treats a zero Light as switched off.
+func (l *Light) currentState() LightState {
+	if l.state == nil {
+		return OffState{}
+	}
+	return l.state
 }
 
 func (l *Light) TurnOn() {
-	l.isOn = true
+	l.state = OnState{}
 	fmt.Println("Light is ON")
 }
 
 func (l *Light) TurnOff() {
-	l.isOn = false
+	l.state = OffState{}
 	fmt.Println("Light is OFF")
 }
 
+// PressSwitch toggles the light by delegating to its current state.
+func (l *Light) PressSwitch() {
+	l.currentState().PressSwitch(l)
+}
+
 func (l *Light) GetStatus() string {
-	if l.isOn {
-		return "ON"
-	}
-	return "OFF"
+	return l.currentState().Status()
 }
 
 // Invoker
 type RemoteControl struct {
+	commands   []Command
+	named      map[string]Command
+	history    []Command
+	redo       []Command
+	maxHistory int              // 0 means unlimited
+	clk        clock.Clock      // nil means clock.System
+	scheduled  []scheduledPress // ordered by time
+	tx         *MacroCommand    // open transaction, if any
+}
+
+var (
+	ErrTransactionActive = errors.New("transaction already in progress")
+	ErrNoTransaction     = errors.New("no transaction in progress")
+)
+
+// MacroCommand runs several commands as one; Undo reverses them in the
+// opposite order.
+type MacroCommand struct {
 	commands []Command
-	history  []Command
+}
+
+func (m *MacroCommand) Execute() {
+	for _, c := range m.commands {
+		c.Execute()
+	}
+}
+
+func (m *MacroCommand) Undo() {
+	for i := len(m.commands) - 1; i >= 0; i-- {
+		m.commands[i].Undo()
+	}
+}
+
+const macroName = "macro"
+
+func (m *MacroCommand) Name() string     { return macroName }
+func (m *MacroCommand) CanExecute() bool { return true }
+
+type scheduledPress struct {
+	at    time.Time
+	index int
 }
 
 func (rc *RemoteControl) SetCommand(command Command) {
 	rc.commands = append(rc.commands, command)
 }
 
-func (rc *RemoteControl) PressButton(index int) {
+func (rc *RemoteControl) SetNamedCommand(name string, command Command) {
+	if rc.named == nil {
+		rc.named = make(map[string]Command)
+	}
+	rc.named[name] = command
+}
+
+// SetMaxHistory caps how many commands can be undone; the oldest are dropped.
+func (rc *RemoteControl) SetMaxHistory(n int) {
+	rc.maxHistory = n
+	rc.trimHistory()
+}
+
+// SetClock replaces the clock used by the scheduler. The default is
+// clock.System.
+func (rc *RemoteControl) SetClock(c clock.Clock) {
+	rc.clk = c
+}
+
+// ScheduleButton queues a press of button index to happen after delay. Queued
+// presses run from RunDue or RunScheduled.
+func (rc *RemoteControl) ScheduleButton(index int, delay time.Duration) {
+	job := scheduledPress{at: clock.Or(rc.clk).Now().Add(delay), index: index}
+	i := sort.Search(len(rc.scheduled), func(i int) bool {
+		return rc.scheduled[i].at.After(job.at)
+	})
+	rc.scheduled = append(rc.scheduled, scheduledPress{})
+	copy(rc.scheduled[i+1:], rc.scheduled[i:])
+	rc.scheduled[i] = job
+}
+
+// RunDue presses every scheduled button whose time has come and returns how
+// many of those commands ran.
+func (rc *RemoteControl) RunDue() int {
+	now := clock.Or(rc.clk).Now()
+	ran := 0
+	for len(rc.scheduled) > 0 && !rc.scheduled[0].at.After(now) {
+		job := rc.scheduled[0]
+		rc.scheduled = rc.scheduled[1:]
+		if rc.PressButton(job.index) {
+			ran++
+		}
+	}
+	return ran
+}
+
+// RunScheduled blocks until every scheduled press has run, waiting on the
+// clock between them. It returns how many commands ran. With a clock.Fake,
+// call BlockUntil(1) before each Advance so the wait is registered first.
+func (rc *RemoteControl) RunScheduled() int {
+	clk := clock.Or(rc.clk)
+	ran := 0
+	for len(rc.scheduled) > 0 {
+		<-clk.After(rc.scheduled[0].at.Sub(clk.Now()))
+		ran += rc.RunDue()
+	}
+	return ran
+}
+
+// BeginTransaction groups the commands run until Commit into a single undo
+// step. Transactions cannot be nested.
+func (rc *RemoteControl) BeginTransaction() error {
+	if rc.tx != nil {
+		return ErrTransactionActive
+	}
+	rc.tx = &MacroCommand{}
+	return nil
+}
+
+// Commit closes the transaction and records its commands as one history
+// entry. An empty transaction records nothing.
+func (rc *RemoteControl) Commit() error {
+	if rc.tx == nil {
+		return ErrNoTransaction
+	}
+	tx := rc.tx
+	rc.tx = nil
+	if len(tx.commands) > 0 {
+		rc.pushHistory(tx)
+	}
+	return nil
+}
+
+// PressButton reports whether the command ran. Commands that cannot execute
+// in the receiver's current state are skipped and not recorded.
+func (rc *RemoteControl) PressButton(index int) bool {
 	if index < len(rc.commands) {
-		rc.commands[index].Execute()
-		rc.history = append(rc.history, rc.commands[index])
+		return rc.run(rc.commands[index])
+	}
+	return false
+}
+
+func (rc *RemoteControl) PressNamedButton(name string) bool {
+	if command, ok := rc.named[name]; ok {
+		return rc.run(command)
+	}
+	return false
+}
+
+func (rc *RemoteControl) run(command Command) bool {
+	if !command.CanExecute() {
+		return false
+	}
+	command.Execute()
+	if rc.tx != nil {
+		rc.tx.commands = append(rc.tx.commands, command)
+	} else {
+		rc.pushHistory(command)
+	}
+	rc.redo = nil
+	return true
+}
+
+func (rc *RemoteControl) pushHistory(command Command) {
+	rc.history = append(rc.history, command)
+	rc.trimHistory()
+}
+
+func (rc *RemoteControl) trimHistory() {
+	if rc.maxHistory > 0 && len(rc.history) > rc.maxHistory {
+		rc.history = rc.history[len(rc.history)-rc.maxHistory:]
 	}
 }
 
-func (rc *RemoteControl) UndoLast() {
+// UndoLast undoes the most recent history entry, if any. It fails with
+// ErrTransactionActive while a transaction is open.
+func (rc *RemoteControl) UndoLast() error {
+	if rc.tx != nil {
+		return ErrTransactionActive
+	}
 	if len(rc.history) > 0 {
 		lastCommand := rc.history[len(rc.history)-1]
 		lastCommand.Undo()
 		rc.history = rc.history[:len(rc.history)-1]
+		rc.redo = append(rc.redo, lastCommand)
+	}
+	return nil
+}
+
+// RedoLast re-executes the most recently undone command, if any. It fails
+// with ErrTransactionActive while a transaction is open.
+func (rc *RemoteControl) RedoLast() error {
+	if rc.tx != nil {
+		return ErrTransactionActive
+	}
+	if len(rc.redo) > 0 {
+		lastCommand := rc.redo[len(rc.redo)-1]
+		lastCommand.Execute()
+		rc.redo = rc.redo[:len(rc.redo)-1]
+		rc.pushHistory(lastCommand)
 	}
+	return nil
+}
+
+// Event Journal - records every executed command so state can be rebuilt
+type JournalEntry struct {
+	Command   string
+	Timestamp time.Time
+}
+
+type EventJournal struct {
+	entries []JournalEntry
+	clk     clock.Clock // nil means clock.System
+}
+
+// SetClock replaces the clock that timestamps entries. The default is
+// clock.System.
+func (j *EventJournal) SetClock(c clock.Clock) {
+	j.clk = c
+}
+
+// lightCommands maps journaled command names to constructors bound to a Light.
+var lightCommands = map[string]func(*Light) Command{
+	"light_on":  func(l *Light) Command { return &LightOnCommand{light: l} },
+	"light_off": func(l *Light) Command { return &LightOffCommand{light: l} },
+}
+
+// Execute runs cmd and appends it to the journal.
+func (j *EventJournal) Execute(cmd Command) {
+	cmd.Execute()
+	j.entries = append(j.entries, JournalEntry{Command: cmd.Name(), Timestamp: clock.Or(j.clk).Now()})
+}
+
+func (j *EventJournal) Entries() []JournalEntry {
+	return append([]JournalEntry(nil), j.entries...)
+}
+
+// Rebuild replays the journal against light, typically a fresh receiver.
+func (j *EventJournal) Rebuild(light *Light) error {
+	for _, entry := range j.entries {
+		newCommand, ok := lightCommands[entry.Command]
+		if !ok {
+			return fmt.Errorf("rebuild: unknown command %q", entry.Command)
+		}
+		newCommand(light).Execute()
+	}
+	return nil
+}
+
+// Builder
+type RemoteControlBuilder struct {
+	remote *RemoteControl
+}
+
+func NewRemoteControlBuilder() *RemoteControlBuilder {
+	return &RemoteControlBuilder{remote: &RemoteControl{}}
+}
+
+func (b *RemoteControlBuilder) WithCommand(cmd Command) *RemoteControlBuilder {
+	b.remote.SetCommand(cmd)
+	return b
+}
+
+func (b *RemoteControlBuilder) WithNamedCommand(name string, cmd Command) *RemoteControlBuilder {
+	b.remote.SetNamedCommand(name, cmd)
+	return b
+}
+
+func (b *RemoteControlBuilder) WithMaxHistory(n int) *RemoteControlBuilder {
+	b.remote.SetMaxHistory(n)
+	return b
+}
+
+func (b *RemoteControlBuilder) WithClock(c clock.Clock) *RemoteControlBuilder {
+	b.remote.SetClock(c)
+	return b
+}
+
+func (b *RemoteControlBuilder) Build() *RemoteControl {
+	return b.remote
+}
+
+// stateEntry is one JSON line of a saved RemoteControl. Entries are written
+// bottom to top, so line order gives each command's position in its stack.
+type stateEntry struct {
+	Stack    string   `json:"stack"`
+	Command  string   `json:"command"`
+	Commands []string `json:"commands,omitempty"` // children of a macro
+}
+
+const (
+	historyStack = "history"
+	redoStack    = "redo"
+)
+
+// SaveState writes the undo and redo stacks as JSON lines of command names.
+// A committed transaction is written as a "macro" line listing its commands.
+// Saving fails with ErrTransactionActive while a transaction is open.
+func (rc *RemoteControl) SaveState(w io.Writer) error {
+	if rc.tx != nil {
+		return fmt.Errorf("save state: %w", ErrTransactionActive)
+	}
+	enc := json.NewEncoder(w)
+	for _, stack := range []struct {
+		name     string
+		commands []Command
+	}{{historyStack, rc.history}, {redoStack, rc.redo}} {
+		for _, cmd := range stack.commands {
+			entry := stateEntry{Stack: stack.name, Command: cmd.Name()}
+			if macro, ok := cmd.(*MacroCommand); ok {
+				for _, child := range macro.commands {
+					entry.Commands = append(entry.Commands, child.Name())
+				}
+			}
+			if err := enc.Encode(entry); err != nil {
+				return fmt.Errorf("save state: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolve looks up the command of entry, rebuilding macros from their
+// children.
+func (e stateEntry) resolve(registry map[string]Command) (Command, error) {
+	if e.Command != macroName {
+		cmd, ok := registry[e.Command]
+		if !ok {
+			return nil, fmt.Errorf("unknown command %q", e.Command)
+		}
+		return cmd, nil
+	}
+	macro := &MacroCommand{}
+	for _, name := range e.Commands {
+		cmd, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown command %q in macro", name)
+		}
+		macro.commands = append(macro.commands, cmd)
+	}
+	return macro, nil
+}
+
+// LoadState replaces the undo and redo stacks with the ones read from r,
+// resolving command names through registry. The remote is left untouched
+// if anything fails.
+func (rc *RemoteControl) LoadState(r io.Reader, registry map[string]Command) error {
+	var history, redo []Command
+	dec := json.NewDecoder(r)
+	for {
+		var entry stateEntry
+		if err := dec.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("load state: %w", err)
+		}
+		cmd, err := entry.resolve(registry)
+		if err != nil {
+			return fmt.Errorf("load state: %w", err)
+		}
+		switch entry.Stack {
+		case historyStack:
+			history = append(history, cmd)
+		case redoStack:
+			redo = append(redo, cmd)
+		default:
+			return fmt.Errorf("load state: unknown stack %q", entry.Stack)
+		}
+	}
+	rc.history, rc.redo = history, redo
+	return nil
 }
 
 func main() {
@@ -116,4 +528,118 @@ func main() {
 	fmt.Println("\nUndoing last command:")
 	remote.UndoLast()
 	fmt.Printf("Light status: %s\n", light.GetStatus())
+
+	// State pattern: the switch toggles based on the current state
+	fmt.Println("\nPressing the wall switch twice:")
+	light.PressSwitch()
+	fmt.Printf("Light status: %s\n", light.GetStatus()) // ON
+	light.PressSwitch()
+	fmt.Printf("Light status: %s\n", light.GetStatus()) // OFF
+
+	// Persist the undo/redo history and resume it on another remote
+	fmt.Println("\nSaving and restoring the remote's history:")
+	remote.PressButton(0)
+	remote.PressButton(1)
+	remote.UndoLast()
+	var saved bytes.Buffer
+	if err := remote.SaveState(&saved); err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Print(saved.String())
+	// {"stack":"history","command":"light_on"}
+	// {"stack":"redo","command":"light_off"}
+
+	resumed := &RemoteControl{}
+	registry := map[string]Command{lightOn.Name(): lightOn, lightOff.Name(): lightOff}
+	if err := resumed.LoadState(&saved, registry); err != nil {
+		fmt.Println("Error:", err)
+	}
+	resumed.RedoLast()
+	fmt.Printf("Light status: %s\n", light.GetStatus()) // OFF
+
+	// Builder: indexed and named buttons with a one-step undo history
+	fmt.Println("\nBuilding a remote with a history cap of 1:")
+	built := NewRemoteControlBuilder().
+		WithCommand(lightOn).
+		WithNamedCommand("off", lightOff).
+		WithMaxHistory(1).
+		Build()
+	built.PressButton(0)
+	built.PressNamedButton("off")
+	built.UndoLast()
+	built.UndoLast()                                    // nothing left to undo
+	fmt.Printf("Light status: %s\n", light.GetStatus()) // ON
+
+	// Event sourcing: replay the journal on a new light
+	fmt.Println("\nReplaying the journal on a new light:")
+	journal := &EventJournal{}
+	journal.Execute(lightOff)
+	journal.Execute(lightOn)
+	replayed := &Light{}
+	if err := journal.Rebuild(replayed); err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Printf("Light status: %s, replayed: %s\n", light.GetStatus(), replayed.GetStatus()) // ON, ON
+
+	// Validation: turning off a light that is already off is a no-op
+	fmt.Println("\nPressing OFF twice:")
+	fmt.Println("Ran:", remote.PressButton(1)) // Ran: true
+	fmt.Println("Ran:", remote.PressButton(1)) // Ran: false
+
+	// Scheduler: a fake clock makes the timing deterministic
+	fmt.Println("\nScheduling presses on a fake clock:")
+	fake := clock.NewFake(time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC))
+	timed := NewRemoteControlBuilder().
+		WithCommand(lightOn).
+		WithCommand(lightOff).
+		WithClock(fake).
+		Build()
+	timed.ScheduleButton(1, 2*time.Hour)
+	timed.ScheduleButton(0, time.Hour)
+	fmt.Println("Ran:", timed.RunDue()) // Ran: 0
+	fake.Advance(time.Hour)
+	fmt.Println("Ran:", timed.RunDue(), "status:", light.GetStatus()) // Ran: 1 status: ON
+	go func() {
+		fake.BlockUntil(1) // wait for RunScheduled to start waiting
+		fake.Advance(time.Hour)
+	}()
+	fmt.Println("Ran:", timed.RunScheduled(), "status:", light.GetStatus()) // Ran: 1 status: OFF
+
+	// Transaction: three presses undone in one step
+	fmt.Println("\nUndoing a transaction:")
+	lamp := &Light{}
+	grouped := NewRemoteControlBuilder().
+		WithCommand(&LightOnCommand{light: lamp}).
+		WithCommand(&LightOffCommand{light: lamp}).
+		Build()
+	if err := grouped.BeginTransaction(); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if err := grouped.BeginTransaction(); err != nil {
+		fmt.Println("Error:", err) // Error: transaction already in progress
+	}
+	grouped.PressButton(0)
+	grouped.PressButton(1)
+	grouped.PressButton(0)
+	if err := grouped.UndoLast(); err != nil {
+		fmt.Println("Error:", err) // Error: transaction already in progress
+	}
+	if err := grouped.Commit(); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	var groupedState bytes.Buffer
+	if err := grouped.SaveState(&groupedState); err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Print(groupedState.String()) // {"stack":"history","command":"macro","commands":["light_on","light_off","light_on"]}
+
+	grouped.UndoLast()
+	fmt.Printf("Light status: %s\n", lamp.GetStatus()) // OFF
+	grouped.UndoLast()                                 // nothing left to undo
+	if err := grouped.Commit(); err != nil {
+		fmt.Println("Error:", err) // Error: no transaction in progress
+	}
 }
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// newTestRemote returns a remote with light_on on button 0 and light_off on
+// button 1, plus the registry LoadState needs.
+func newTestRemote() (*RemoteControl, *Light, map[string]Command) {
+	light := &Light{}
+	on, off := &LightOnCommand{light: light}, &LightOffCommand{light: light}
+	rc := NewRemoteControlBuilder().WithCommand(on).WithCommand(off).Build()
+	return rc, light, map[string]Command{on.Name(): on, off.Name(): off}
+}
+
+func TestTransactionUndoesAsOneStep(t *testing.T) {
+	rc, light, _ := newTestRemote()
+	if err := rc.BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	rc.PressButton(0)
+	rc.PressButton(1)
+	rc.PressButton(0)
+	if err := rc.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := light.GetStatus(); got != "ON" {
+		t.Fatalf("status after commit = %s, want ON", got)
+	}
+	if err := rc.UndoLast(); err != nil {
+		t.Fatal(err)
+	}
+	if got := light.GetStatus(); got != "OFF" {
+		t.Errorf("status after one undo = %s, want OFF", got)
+	}
+	if len(rc.history) != 0 {
+		t.Errorf("history has %d entries, want 0", len(rc.history))
+	}
+}
+
+func TestTransactionErrors(t *testing.T) {
+	rc, _, _ := newTestRemote()
+	if err := rc.Commit(); !errors.Is(err, ErrNoTransaction) {
+		t.Errorf("Commit without Begin = %v, want ErrNoTransaction", err)
+	}
+	if err := rc.BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"nested Begin", rc.BeginTransaction},
+		{"UndoLast", rc.UndoLast},
+		{"RedoLast", rc.RedoLast},
+		{"SaveState", func() error { return rc.SaveState(&bytes.Buffer{}) }},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, ErrTransactionActive) {
+			t.Errorf("%s during a transaction = %v, want ErrTransactionActive", tt.name, err)
+		}
+	}
+}
+
+func TestSaveLoadStateWithTransaction(t *testing.T) {
+	rc, light, registry := newTestRemote()
+	rc.PressButton(0)
+	rc.BeginTransaction()
+	rc.PressButton(1)
+	rc.PressButton(0)
+	rc.Commit()
+
+	var buf bytes.Buffer
+	if err := rc.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"stack":"history","command":"light_on"}` + "\n" +
+		`{"stack":"history","command":"macro","commands":["light_off","light_on"]}` + "\n"
+	if buf.String() != want {
+		t.Fatalf("SaveState wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	resumed := &RemoteControl{}
+	if err := resumed.LoadState(&buf, registry); err != nil {
+		t.Fatal(err)
+	}
+	resumed.UndoLast() // undoes the whole macro: off, then on
+	if got := light.GetStatus(); got != "ON" {
+		t.Errorf("status after undoing macro = %s, want ON", got)
+	}
+	resumed.UndoLast()
+	if got := light.GetStatus(); got != "OFF" {
+		t.Errorf("status after undoing light_on = %s, want OFF", got)
+	}
+}
+
+func TestLoadStateUnknownMacroChild(t *testing.T) {
+	_, _, registry := newTestRemote()
+	rc := &RemoteControl{}
+	in := `{"stack":"history","command":"macro","commands":["light_on","dim"]}` + "\n"
+	if err := rc.LoadState(bytes.NewBufferString(in), registry); err == nil {
+		t.Error("LoadState accepted a macro with an unknown command")
+	}
+}
+
+func TestSchedulerWithFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	rc, light, _ := newTestRemote()
+	rc.SetClock(fake)
+	rc.ScheduleButton(1, 2*time.Hour)
+	rc.ScheduleButton(0, time.Hour)
+
+	steps := []struct {
+		advance    time.Duration
+		wantRan    int
+		wantStatus string
+	}{
+		{0, 0, "OFF"},
+		{59 * time.Minute, 0, "OFF"},
+		{time.Minute, 1, "ON"},
+		{time.Hour, 1, "OFF"},
+		{time.Hour, 0, "OFF"},
+	}
+	for i, s := range steps {
+		fake.Advance(s.advance)
+		if got := rc.RunDue(); got != s.wantRan {
+			t.Errorf("step %d: RunDue() = %d, want %d", i, got, s.wantRan)
+		}
+		if got := light.GetStatus(); got != s.wantStatus {
+			t.Errorf("step %d: status = %s, want %s", i, got, s.wantStatus)
+		}
+	}
+}
+
+func TestRunScheduledWaitsOnClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	rc, light, _ := newTestRemote()
+	rc.SetClock(fake)
+	rc.ScheduleButton(0, time.Minute)
+	rc.ScheduleButton(1, 2*time.Minute)
+
+	done := make(chan int)
+	go func() { done <- rc.RunScheduled() }()
+	for i := 0; i < 2; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+	}
+	select {
+	case ran := <-done:
+		if ran != 2 || light.GetStatus() != "OFF" {
+			t.Errorf("RunScheduled() = %d, status %s; want 2, OFF", ran, light.GetStatus())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RunScheduled did not return")
+	}
+}
+
+func TestEventJournalUsesClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	light := &Light{}
+	j := &EventJournal{}
+	j.SetClock(fake)
+	j.Execute(&LightOnCommand{light: light})
+	fake.Advance(time.Minute)
+	j.Execute(&LightOffCommand{light: light})
+
+	entries := j.Entries()
+	want := []JournalEntry{
+		{Command: "light_on", Timestamp: start},
+		{Command: "light_off", Timestamp: start.Add(time.Minute)},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Entries() = %v, want %v", entries, want)
+	}
+	for i := range want {
+		if entries[i].Command != want[i].Command || !entries[i].Timestamp.Equal(want[i].Timestamp) {
+			t.Errorf("entry %d = %v, want %v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLightState(t *testing.T) {
+	light := &Light{}
+	tests := []struct {
+		action func()
+		want   string
+	}{
+		{func() {}, "OFF"}, // zero Light is off
+		{light.PressSwitch, "ON"},
+		{light.PressSwitch, "OFF"},
+		{light.TurnOn, "ON"},
+		{light.TurnOn, "ON"},
+		{light.PressSwitch, "OFF"},
+	}
+	for i, tt := range tests {
+		tt.action()
+		if got := light.GetStatus(); got != tt.want {
+			t.Errorf("step %d: status = %s, want %s", i, got, tt.want)
+		}
+	}
+}
+
+func TestPressButtonSkipsInvalidCommands(t *testing.T) {
+	rc, light, _ := newTestRemote()
+	tests := []struct {
+		name    string
+		press   func() bool
+		wantRan bool
+		want    string
+		history int
+	}{
+		{"off while off", func() bool { return rc.PressButton(1) }, false, "OFF", 0},
+		{"on while off", func() bool { return rc.PressButton(0) }, true, "ON", 1},
+		{"on while on", func() bool { return rc.PressButton(0) }, false, "ON", 1},
+		{"out of range", func() bool { return rc.PressButton(5) }, false, "ON", 1},
+		{"unknown name", func() bool { return rc.PressNamedButton("fan") }, false, "ON", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.press(); got != tt.wantRan {
+				t.Errorf("ran = %v, want %v", got, tt.wantRan)
+			}
+			if got := light.GetStatus(); got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+			if len(rc.history) != tt.history {
+				t.Errorf("history has %d entries, want %d", len(rc.history), tt.history)
+			}
+		})
+	}
+}
+
+func TestNamedButtons(t *testing.T) {
+	light := &Light{}
+	rc := NewRemoteControlBuilder().
+		WithNamedCommand("on", &LightOnCommand{light: light}).
+		WithNamedCommand("off", &LightOffCommand{light: light}).
+		Build()
+	rc.PressNamedButton("on")
+	if got := light.GetStatus(); got != "ON" {
+		t.Fatalf("status after on = %s, want ON", got)
+	}
+	rc.PressNamedButton("off")
+	if got := light.GetStatus(); got != "OFF" {
+		t.Fatalf("status after off = %s, want OFF", got)
+	}
+	rc.UndoLast()
+	if got := light.GetStatus(); got != "ON" {
+		t.Errorf("status after undo = %s, want ON", got)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	rc, light, _ := newTestRemote()
+	rc.PressButton(0)
+	rc.PressButton(1)
+
+	steps := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"undo off", rc.UndoLast, "ON"},
+		{"undo on", rc.UndoLast, "OFF"},
+		{"undo on empty history", rc.UndoLast, "OFF"},
+		{"redo on", rc.RedoLast, "ON"},
+		{"redo off", rc.RedoLast, "OFF"},
+		{"redo on empty stack", rc.RedoLast, "OFF"},
+	}
+	for _, s := range steps {
+		if err := s.call(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if got := light.GetStatus(); got != s.want {
+			t.Errorf("%s: status = %s, want %s", s.name, got, s.want)
+		}
+	}
+
+	// A new press clears the redo stack.
+	rc.UndoLast()
+	rc.PressButton(1)
+	if len(rc.redo) != 0 {
+		t.Errorf("redo has %d entries after a new press, want 0", len(rc.redo))
+	}
+}
+
+func TestMaxHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		max         int
+		presses     int
+		wantHistory int
+	}{
+		{"unlimited", 0, 6, 6},
+		{"capped", 2, 6, 2},
+		{"under the cap", 10, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			light := &Light{}
+			rc := NewRemoteControlBuilder().
+				WithCommand(&LightOnCommand{light: light}).
+				WithCommand(&LightOffCommand{light: light}).
+				WithMaxHistory(tt.max).
+				Build()
+			for i := 0; i < tt.presses; i++ {
+				rc.PressButton(i % 2)
+			}
+			if len(rc.history) != tt.wantHistory {
+				t.Errorf("history has %d entries, want %d", len(rc.history), tt.wantHistory)
+			}
+		})
+	}
+
+	// Lowering the cap trims what is already recorded.
+	rc, _, _ := newTestRemote()
+	for i := 0; i < 4; i++ {
+		rc.PressButton(i % 2)
+	}
+	rc.SetMaxHistory(1)
+	if len(rc.history) != 1 || rc.history[0].Name() != "light_off" {
+		t.Errorf("history after SetMaxHistory(1) = %v", rc.history)
+	}
+}
+
+func TestMacroCommandUndoOrder(t *testing.T) {
+	var log []string
+	macro := &MacroCommand{commands: []Command{
+		&loggingCommand{name: "a", log: &log},
+		&loggingCommand{name: "b", log: &log},
+	}}
+	macro.Execute()
+	macro.Undo()
+	if want := []string{"do a", "do b", "undo b", "undo a"}; !slices.Equal(log, want) {
+		t.Errorf("log = %v, want %v", log, want)
+	}
+}
+
+// loggingCommand appends what it does to log.
+type loggingCommand struct {
+	name string
+	log  *[]string
+}
+
+func (c *loggingCommand) Execute()         { *c.log = append(*c.log, "do "+c.name) }
+func (c *loggingCommand) Undo()            { *c.log = append(*c.log, "undo "+c.name) }
+func (c *loggingCommand) Name() string     { return c.name }
+func (c *loggingCommand) CanExecute() bool { return true }
+
+func TestLoadStateErrors(t *testing.T) {
+	tests := []struct {
+		name, in, wantErr string
+	}{
+		{"malformed", `{"stack":`, "load state:"},
+		{"unknown command", `{"stack":"history","command":"fan_on"}`, `unknown command "fan_on"`},
+		{"unknown stack", `{"stack":"future","command":"light_on"}`, `unknown stack "future"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, _, registry := newTestRemote()
+			rc.PressButton(0)
+			err := rc.LoadState(bytes.NewBufferString(tt.in), registry)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadState() = %v, want error containing %q", err, tt.wantErr)
+			}
+			if len(rc.history) != 1 {
+				t.Errorf("failed load changed history to %v", rc.history)
+			}
+		})
+	}
+
+	rc, _, registry := newTestRemote()
+	rc.PressButton(0)
+	if err := rc.LoadState(&bytes.Buffer{}, registry); err != nil || len(rc.history) != 0 {
+		t.Errorf("empty input: err %v, history %v", err, rc.history)
+	}
+}
+
+func TestEventJournalRebuild(t *testing.T) {
+	j := &EventJournal{}
+	light := &Light{}
+	j.Execute(&LightOnCommand{light: light})
+	j.Execute(&LightOffCommand{light: light})
+	j.Execute(&LightOnCommand{light: light})
+
+	fresh := &Light{}
+	if err := j.Rebuild(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.GetStatus() != light.GetStatus() {
+		t.Errorf("rebuilt status = %s, want %s", fresh.GetStatus(), light.GetStatus())
+	}
+
+	entries := j.Entries()
+	entries[0].Command = "tampered"
+	if j.Entries()[0].Command != "light_on" {
+		t.Error("Entries returned the journal's own slice")
+	}
+
+	var log []string
+	j.Execute(&loggingCommand{name: "fan_on", log: &log})
+	if err := j.Rebuild(&Light{}); err == nil || !strings.Contains(err.Error(), `"fan_on"`) {
+		t.Errorf("Rebuild with an unknown command = %v", err)
+	}
+}
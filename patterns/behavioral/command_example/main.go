@@ -1,6 +1,15 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// now is the clock used by Throttle; replace it to control time.
+var now = time.Now
 
 // Command interface (usado como "Observer")
 type NotificationCommand interface {
@@ -24,6 +33,21 @@ func (p *PushNotification) Execute(data string) {
 	fmt.Println("Push notification:", data)
 }
 
+// Composite Command: un solo registro dispara varios canales
+type MultiChannelNotifier struct {
+	channels []NotificationCommand
+}
+
+func NewMultiChannelNotifier(channels ...NotificationCommand) *MultiChannelNotifier {
+	return &MultiChannelNotifier{channels: channels}
+}
+
+func (m *MultiChannelNotifier) Execute(data string) {
+	for _, ch := range m.channels {
+		ch.Execute(data)
+	}
+}
+
 // Invoker (como "Subject")
 type NotificationCenter struct {
 	commands []NotificationCommand
@@ -39,6 +63,87 @@ func (nc *NotificationCenter) NotifyAll(message string) {
 	}
 }
 
+// DedupNotificationCenter drops a NotifyAll whose message was already sent
+// within window. It is safe for concurrent use.
+type DedupNotificationCenter struct {
+	*NotificationCenter
+	window time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewDedupNotificationCenter wraps center. A nil now uses time.Now.
+func NewDedupNotificationCenter(center *NotificationCenter, window time.Duration, now func() time.Time) *DedupNotificationCenter {
+	if now == nil {
+		now = time.Now
+	}
+	return &DedupNotificationCenter{
+		NotificationCenter: center,
+		window:             window,
+		now:                now,
+		lastSent:           make(map[string]time.Time),
+	}
+}
+
+// NotifyAll reports whether the message was delivered or suppressed as a duplicate.
+func (d *DedupNotificationCenter) NotifyAll(message string) bool {
+	d.mu.Lock()
+	t := d.now()
+	d.evictLocked(t)
+	if _, ok := d.lastSent[message]; ok {
+		d.mu.Unlock()
+		return false
+	}
+	d.lastSent[message] = t
+	d.mu.Unlock()
+	d.NotificationCenter.NotifyAll(message)
+	return true
+}
+
+// evictLocked forgets messages sent a full window or more before t, so
+// lastSent only holds messages that can still be suppressed. d.mu must be
+// held.
+func (d *DedupNotificationCenter) evictLocked(t time.Time) {
+	for message, last := range d.lastSent {
+		if t.Sub(last) >= d.window {
+			delete(d.lastSent, message)
+		}
+	}
+}
+
+// Throttle forwards a call to fn only if interval has elapsed since the
+// last forwarded call; calls in between are dropped. The returned func is
+// safe for concurrent use.
+func Throttle(fn func(string), interval time.Duration) func(string) {
+	return throttle(func() time.Time { return now() }, fn, interval)
+}
+
+// ThrottleWithClock is like Throttle but reads the time from clk. A nil clk
+// uses clock.System.
+func ThrottleWithClock(clk clock.Clock, fn func(string), interval time.Duration) func(string) {
+	return throttle(clock.Or(clk).Now, fn, interval)
+}
+
+func throttle(now func() time.Time, fn func(string), interval time.Duration) func(string) {
+	var (
+		mu   sync.Mutex
+		last time.Time
+	)
+	return func(data string) {
+		mu.Lock()
+		t := now()
+		if !last.IsZero() && t.Sub(last) < interval {
+			mu.Unlock()
+			return
+		}
+		last = t
+		mu.Unlock()
+		fn(data)
+	}
+}
+
 func main() {
 	center := &NotificationCenter{}
 
@@ -49,4 +154,20 @@ func main() {
 
 	// Notificar a todos (como Observer)
 	center.NotifyAll("New message received!")
+
+	// Throttle rapid triggers of the same command
+	email := Throttle((&EmailNotification{}).Execute, time.Second)
+	email("Build failed") // forwarded
+	email("Build failed") // dropped, within 1s
+	email("Build fixed")  // dropped, within 1s
+
+	// De-duplicate identical messages within a window
+	dedup := NewDedupNotificationCenter(center, time.Minute, nil)
+	fmt.Println("Delivered:", dedup.NotifyAll("Disk almost full")) // Delivered: true
+	fmt.Println("Delivered:", dedup.NotifyAll("Disk almost full")) // Delivered: false
+
+	// One registration, three channels
+	alerts := &NotificationCenter{}
+	alerts.Register(NewMultiChannelNotifier(&EmailNotification{}, &SMSNotification{}, &PushNotification{}))
+	alerts.NotifyAll("Payment received")
 }
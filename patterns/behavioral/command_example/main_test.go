@@ -0,0 +1,223 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// recorder is a NotificationCommand that remembers what it received.
+type recorder struct {
+	got []string
+}
+
+func (r *recorder) Execute(data string) {
+	r.got = append(r.got, data)
+}
+
+// fakeNow replaces the package clock with one that only moves when the
+// returned advance func is called.
+func fakeNow(t *testing.T) (advance func(time.Duration)) {
+	t.Helper()
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orig := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = orig })
+	return func(d time.Duration) { current = current.Add(d) }
+}
+
+func TestThrottle(t *testing.T) {
+	advance := fakeNow(t)
+	rec := &recorder{}
+	throttled := Throttle(rec.Execute, time.Second)
+
+	steps := []struct {
+		advance time.Duration
+		data    string
+	}{
+		{0, "a"},                      // first call always passes
+		{500 * time.Millisecond, "b"}, // dropped
+		{499 * time.Millisecond, "c"}, // dropped, 999ms since a
+		{time.Millisecond, "d"},       // 1s since a
+		{2 * time.Second, "e"},
+	}
+	for _, s := range steps {
+		advance(s.advance)
+		throttled(s.data)
+	}
+	if want := []string{"a", "d", "e"}; !slices.Equal(rec.got, want) {
+		t.Errorf("forwarded %v, want %v", rec.got, want)
+	}
+}
+
+func TestThrottleWithClock(t *testing.T) {
+	tests := []struct {
+		name string
+		clk  clock.Clock
+		want []string
+	}{
+		{"fake clock", clock.NewFake(time.Unix(0, 0)), []string{"a", "c"}},
+		{"nil clock uses the system clock", nil, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			throttled := ThrottleWithClock(tt.clk, rec.Execute, time.Hour)
+			throttled("a")
+			throttled("b")
+			if fake, ok := tt.clk.(*clock.Fake); ok {
+				fake.Advance(time.Hour)
+				throttled("c")
+			}
+			if !slices.Equal(rec.got, tt.want) {
+				t.Errorf("forwarded %v, want %v", rec.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottleConcurrent(t *testing.T) {
+	var forwarded atomic.Int32
+	throttled := ThrottleWithClock(clock.NewFake(time.Unix(0, 0)), func(string) { forwarded.Add(1) }, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			throttled("x")
+		}()
+	}
+	wg.Wait()
+	if got := forwarded.Load(); got != 1 {
+		t.Errorf("forwarded %d calls, want 1", got)
+	}
+}
+
+func TestDedupNotificationCenter(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec := &recorder{}
+	center := &NotificationCenter{}
+	center.Register(rec)
+	dedup := NewDedupNotificationCenter(center, time.Minute, func() time.Time { return current })
+
+	steps := []struct {
+		advance time.Duration
+		message string
+		want    bool
+	}{
+		{0, "disk", true},
+		{30 * time.Second, "disk", false},
+		{0, "cpu", true},
+		{30 * time.Second, "disk", true}, // window elapsed
+		{time.Second, "disk", false},
+	}
+	for i, s := range steps {
+		current = current.Add(s.advance)
+		if got := dedup.NotifyAll(s.message); got != s.want {
+			t.Errorf("step %d: NotifyAll(%q) = %v, want %v", i, s.message, got, s.want)
+		}
+	}
+	if want := []string{"disk", "cpu", "disk"}; !slices.Equal(rec.got, want) {
+		t.Errorf("delivered %v, want %v", rec.got, want)
+	}
+}
+
+func TestThrottleNonPositiveInterval(t *testing.T) {
+	fakeNow(t)
+	for _, interval := range []time.Duration{0, -time.Second} {
+		rec := &recorder{}
+		throttled := Throttle(rec.Execute, interval)
+		for _, data := range []string{"a", "b", "c"} {
+			throttled(data)
+		}
+		if want := []string{"a", "b", "c"}; !slices.Equal(rec.got, want) {
+			t.Errorf("interval %v forwarded %v, want %v", interval, rec.got, want)
+		}
+	}
+}
+
+func TestDedupEvictsExpiredMessages(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dedup := NewDedupNotificationCenter(&NotificationCenter{}, time.Minute, func() time.Time { return current })
+	for _, message := range []string{"a", "b", "c"} {
+		dedup.NotifyAll(message)
+	}
+	current = current.Add(time.Minute)
+	dedup.NotifyAll("d")
+	if got := len(dedup.lastSent); got != 1 {
+		t.Errorf("lastSent holds %d messages after the window, want 1", got)
+	}
+}
+
+func TestDedupConcurrent(t *testing.T) {
+	var delivered atomic.Int32
+	center := &NotificationCenter{}
+	center.Register(counter{&delivered})
+	dedup := NewDedupNotificationCenter(center, time.Hour, nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dedup.NotifyAll("same")
+		}()
+	}
+	wg.Wait()
+	if got := delivered.Load(); got != 1 {
+		t.Errorf("delivered %d times, want 1", got)
+	}
+}
+
+// counter is a NotificationCommand that counts its executions.
+type counter struct {
+	n *atomic.Int32
+}
+
+func (c counter) Execute(string) { c.n.Add(1) }
+
+func TestDedupZeroWindow(t *testing.T) {
+	rec := &recorder{}
+	center := &NotificationCenter{}
+	center.Register(rec)
+	dedup := NewDedupNotificationCenter(center, 0, nil)
+	for i := 0; i < 3; i++ {
+		if !dedup.NotifyAll("same") {
+			t.Errorf("call %d suppressed with a zero window", i)
+		}
+	}
+	if len(rec.got) != 3 {
+		t.Errorf("delivered %v, want 3 messages", rec.got)
+	}
+}
+
+func TestNotificationCenterFanOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels int
+	}{
+		{"no channels", 0},
+		{"one channel", 1},
+		{"three channels", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := make([]NotificationCommand, tt.channels)
+			for i := range recs {
+				recs[i] = &recorder{}
+			}
+			center := &NotificationCenter{}
+			center.Register(NewMultiChannelNotifier(recs...))
+			center.Register(NewMultiChannelNotifier(recs...))
+			center.NotifyAll("m")
+			for i, r := range recs {
+				if got := r.(*recorder).got; !slices.Equal(got, []string{"m", "m"}) {
+					t.Errorf("channel %d got %v, want one message per registration", i, got)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,17 @@
 package main
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
 
 // ===== FACTORY PATTERN =====
 // Creates different types of payment processors
@@ -42,6 +53,188 @@ func NewPaymentProcessor(provider string) (PaymentProcessor, error) {
 	}
 }
 
+// ===== ADAPTER PATTERN =====
+// Lets third-party processors with a cent-based API act as a PaymentProcessor
+
+type LegacyProcessor interface {
+	Charge(cents int) error
+}
+
+type LegacyBankGateway struct{}
+
+func (g LegacyBankGateway) Charge(cents int) error {
+	fmt.Printf("[LegacyBank] Charging %d cents\n", cents)
+	return nil
+}
+
+type LegacyProcessorAdapter struct {
+	legacy LegacyProcessor
+}
+
+func NewLegacyProcessorAdapter(legacy LegacyProcessor) *LegacyProcessorAdapter {
+	return &LegacyProcessorAdapter{legacy: legacy}
+}
+
+func (a *LegacyProcessorAdapter) ProcessPayment(amount float64) error {
+	return a.legacy.Charge(toCents(amount))
+}
+
+// toCents rounds to the nearest cent, halves away from zero. It rounds the
+// shortest decimal form of amount rather than amount*100, so 12.345, stored
+// as 12.34499..., still rounds up to 1235 at any magnitude.
+func toCents(amount float64) int {
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(math.Abs(amount), 'f', -1, 64), ".")
+	frac += "000"
+	cents, _ := strconv.Atoi(whole + frac[:2])
+	if frac[2] >= '5' {
+		cents++
+	}
+	if amount < 0 {
+		cents = -cents
+	}
+	return cents
+}
+
+// ===== IDEMPOTENCY =====
+// Retries with the same key must not charge twice
+
+type IdempotentProcessor struct {
+	processor PaymentProcessor
+	mu        sync.Mutex
+	results   map[string]*idempotentCall // in-flight and successful charges
+}
+
+// idempotentCall is the charge in flight for one key, or the one that
+// succeeded. done is closed once err is final.
+type idempotentCall struct {
+	done chan struct{}
+	err  error
+}
+
+func NewIdempotentProcessor(processor PaymentProcessor) *IdempotentProcessor {
+	return &IdempotentProcessor{
+		processor: processor,
+		results:   make(map[string]*idempotentCall),
+	}
+}
+
+// ErrChargeAborted is returned to callers that waited on a charge whose
+// processor panicked.
+var ErrChargeAborted = errors.New("charge aborted: payment processor panicked")
+
+// ProcessPaymentIdempotent charges amount once per key. Once a charge for key
+// has succeeded, repeated calls return nil without charging; calls that
+// arrive while the charge is in flight wait for its result. Failed charges
+// are not remembered, so a later call with the same key tries again.
+// Different keys are charged concurrently.
+func (ip *IdempotentProcessor) ProcessPaymentIdempotent(key string, amount float64) error {
+	ip.mu.Lock()
+	if call, seen := ip.results[key]; seen {
+		ip.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &idempotentCall{done: make(chan struct{}), err: ErrChargeAborted}
+	ip.results[key] = call
+	ip.mu.Unlock()
+
+	// Runs even if the processor panics, so waiters are always released and
+	// the key is not left in flight.
+	defer func() {
+		if call.err != nil {
+			ip.mu.Lock()
+			delete(ip.results, key)
+			ip.mu.Unlock()
+		}
+		close(call.done)
+	}()
+	call.err = ip.processor.ProcessPayment(amount)
+	return call.err
+}
+
+// ===== CIRCUIT BREAKER =====
+// Stops calling a failing provider until it has had time to recover
+
+var ErrCircuitOpen = errors.New("circuit open: provider temporarily unavailable")
+
+// ProcessorFunc lets an ordinary function act as a PaymentProcessor.
+type ProcessorFunc func(amount float64) error
+
+func (f ProcessorFunc) ProcessPayment(amount float64) error {
+	return f(amount)
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerProcessor opens after maxFailures consecutive failures and
+// rejects calls with ErrCircuitOpen for cooldown. After that a single trial
+// call is let through: success closes the circuit, failure opens it again.
+type CircuitBreakerProcessor struct {
+	processor   PaymentProcessor
+	maxFailures int
+	cooldown    time.Duration
+	clk         clock.Clock // nil means clock.System
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func NewCircuitBreakerProcessor(processor PaymentProcessor, maxFailures int, cooldown time.Duration) *CircuitBreakerProcessor {
+	return &CircuitBreakerProcessor{
+		processor:   processor,
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+	}
+}
+
+// SetClock replaces the clock used to time the cooldown. The default is
+// clock.System.
+func (cb *CircuitBreakerProcessor) SetClock(c clock.Clock) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.clk = c
+}
+
+func (cb *CircuitBreakerProcessor) ProcessPayment(amount float64) error {
+	cb.mu.Lock()
+	if cb.state == circuitOpen {
+		if clock.Or(cb.clk).Now().Sub(cb.openedAt) < cb.cooldown {
+			cb.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+	} else if cb.state == circuitHalfOpen {
+		// A trial call is already in flight.
+		cb.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	cb.mu.Unlock()
+
+	err := cb.processor.ProcessPayment(amount)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err == nil {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return nil
+	}
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.maxFailures {
+		cb.state = circuitOpen
+		cb.openedAt = clock.Or(cb.clk).Now()
+	}
+	return err
+}
+
 // ===== STRATEGY PATTERN =====
 // Different pricing strategies for the same payment processor
 
@@ -49,26 +242,401 @@ type PricingStrategy interface {
 	CalculatePrice(amount float64) float64
 }
 
-type StandardPricing struct{}
-type PremiumPricing struct{}
-type DiscountPricing struct{}
+// ComparePricing returns the price amount would have under each named strategy.
+func ComparePricing(amount float64, strategies map[string]PricingStrategy) map[string]float64 {
+	prices := make(map[string]float64, len(strategies))
+	for name, strategy := range strategies {
+		prices[name] = strategy.CalculatePrice(amount)
+	}
+	return prices
+}
+
+// WeightedStrategy pairs a strategy with its share of traffic.
+type WeightedStrategy struct {
+	Strategy PricingStrategy
+	Weight   int
+}
+
+// WeightedStrategySelector hands out strategies in proportion to their
+// weights. It uses a counter instead of randomness, so each cycle of
+// total-weight calls returns every strategy exactly Weight times.
+type WeightedStrategySelector struct {
+	mu      sync.Mutex
+	entries []WeightedStrategy
+	total   int
+	counter int
+}
+
+func NewWeightedStrategySelector(entries ...WeightedStrategy) (*WeightedStrategySelector, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("at least one strategy is required")
+	}
+	total := 0
+	for _, e := range entries {
+		if e.Weight <= 0 {
+			return nil, fmt.Errorf("invalid weight %d: must be positive", e.Weight)
+		}
+		total += e.Weight
+	}
+	return &WeightedStrategySelector{entries: entries, total: total}, nil
+}
+
+func (w *WeightedStrategySelector) Next() PricingStrategy {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	slot := w.counter % w.total
+	w.counter++
+	for _, e := range w.entries {
+		if slot < e.Weight {
+			return e.Strategy
+		}
+		slot -= e.Weight
+	}
+	return w.entries[len(w.entries)-1].Strategy
+}
+
+// ===== TEMPLATE METHOD =====
+// BasePricing holds the skeleton shared by the pricing strategies:
+// validate the amount, apply the strategy's rate and round to cents.
+
+// rateHook is the step each concrete strategy overrides.
+type rateHook interface {
+	rate() float64
+}
+
+type BasePricing struct {
+	hook rateHook
+}
+
+// bind returns a BasePricing whose Compute uses h for the rate step.
+func (b BasePricing) bind(h rateHook) BasePricing {
+	b.hook = h
+	return b
+}
+
+// Compute is the template method. Negative amounts are treated as zero and
+// a missing hook applies no rate.
+func (b BasePricing) Compute(amount float64) float64 {
+	if amount < 0 {
+		amount = 0
+	}
+	rate := 1.0
+	if b.hook != nil {
+		rate = b.hook.rate()
+	}
+	return float64(toCents(amount*rate)) / 100
+}
+
+type StandardPricing struct{ BasePricing }
+type PremiumPricing struct{ BasePricing }
+type DiscountPricing struct{ BasePricing }
+
+func (s StandardPricing) rate() float64 { return 1.02 } // 2% fee
+func (p PremiumPricing) rate() float64  { return 1.05 } // 5% fee
+func (d DiscountPricing) rate() float64 { return 0.98 } // 2% discount
 
 func (s StandardPricing) CalculatePrice(amount float64) float64 {
-	return amount * 1.02 // 2% fee
+	return s.bind(s).Compute(amount)
 }
 
 func (p PremiumPricing) CalculatePrice(amount float64) float64 {
-	return amount * 1.05 // 5% fee
+	return p.bind(p).Compute(amount)
 }
 
 func (d DiscountPricing) CalculatePrice(amount float64) float64 {
-	return amount * 0.98 // 2% discount
+	return d.bind(d).Compute(amount)
+}
+
+// PricingTier applies Rate to amounts in [Min, Max).
+type PricingTier struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Rate float64 `json:"rate"`
+}
+
+// JSONTieredPricing picks the rate of the tier the amount falls in. Amounts
+// outside every tier are charged as is.
+type JSONTieredPricing struct {
+	tiers []PricingTier
+}
+
+// NewJSONTieredPricing loads tiers such as
+// [{"min": 0, "max": 100, "rate": 1.05}]. Tiers must be listed in ascending
+// order and must not overlap.
+func NewJSONTieredPricing(data []byte) (PricingStrategy, error) {
+	var tiers []PricingTier
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return nil, fmt.Errorf("tiers: %w", err)
+	}
+	for i, t := range tiers {
+		if t.Min >= t.Max {
+			return nil, fmt.Errorf("tier %d: min %.2f must be below max %.2f", i, t.Min, t.Max)
+		}
+		if t.Rate < 0 {
+			return nil, fmt.Errorf("tier %d: invalid rate %.2f", i, t.Rate)
+		}
+		if i > 0 && t.Min < tiers[i-1].Max {
+			return nil, fmt.Errorf("tier %d: overlaps or precedes tier %d", i, i-1)
+		}
+	}
+	return JSONTieredPricing{tiers: tiers}, nil
+}
+
+func (j JSONTieredPricing) CalculatePrice(amount float64) float64 {
+	if amount < 0 {
+		amount = 0
+	}
+	rate := 1.0
+	for _, t := range j.tiers {
+		if amount >= t.Min && amount < t.Max {
+			rate = t.Rate
+			break
+		}
+	}
+	return float64(toCents(amount*rate)) / 100
+}
+
+// ===== CHAIN OF RESPONSIBILITY =====
+// Large payments must be approved before they are processed
+
+const maxApprovableAmount = 100000
+
+type ApprovalHandler interface {
+	SetNext(next ApprovalHandler) ApprovalHandler
+	Approve(amount float64) error
+}
+
+// baseApproval holds the link to the next handler in the chain.
+type baseApproval struct {
+	next ApprovalHandler
+}
+
+func (b *baseApproval) SetNext(next ApprovalHandler) ApprovalHandler {
+	b.next = next
+	return next
+}
+
+func (b *baseApproval) passToNext(amount float64) error {
+	if b.next == nil {
+		return nil
+	}
+	return b.next.Approve(amount)
+}
+
+// ManagerApproval signs off amounts over $1000.
+type ManagerApproval struct {
+	baseApproval
+}
+
+func (m *ManagerApproval) Approve(amount float64) error {
+	if amount > 1000 {
+		fmt.Printf("[Manager] Approved $%.2f\n", amount)
+	}
+	return m.passToNext(amount)
+}
+
+// DirectorApproval signs off amounts over $10000 and rejects anything
+// above maxApprovableAmount.
+type DirectorApproval struct {
+	baseApproval
+}
+
+func (d *DirectorApproval) Approve(amount float64) error {
+	if amount > maxApprovableAmount {
+		return fmt.Errorf("director rejected $%.2f: above limit of $%d", amount, maxApprovableAmount)
+	}
+	if amount > 10000 {
+		fmt.Printf("[Director] Approved $%.2f\n", amount)
+	}
+	return d.passToNext(amount)
+}
+
+// ===== INTERPRETER PATTERN =====
+// Fee rules written as expressions, e.g. "amount * 1.02 + 5"
+
+type Expression interface {
+	Interpret(amount float64) float64
+}
+
+type numberExpr float64
+
+func (n numberExpr) Interpret(amount float64) float64 { return float64(n) }
+
+type amountExpr struct{}
+
+func (a amountExpr) Interpret(amount float64) float64 { return amount }
+
+type negateExpr struct{ operand Expression }
+
+func (n negateExpr) Interpret(amount float64) float64 { return -n.operand.Interpret(amount) }
+
+type binaryExpr struct {
+	op          byte
+	left, right Expression
+}
+
+func (b binaryExpr) Interpret(amount float64) float64 {
+	l, r := b.left.Interpret(amount), b.right.Interpret(amount)
+	switch b.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	default:
+		return l / r
+	}
+}
+
+type ExpressionPricing struct {
+	expr Expression
+}
+
+func (e ExpressionPricing) CalculatePrice(amount float64) float64 {
+	return e.expr.Interpret(amount)
+}
+
+// NewExpressionPricing parses expr into a pricing strategy. Expressions may
+// use amount, numeric literals, parentheses and + - * / with the usual
+// precedence.
+func NewExpressionPricing(expr string) (PricingStrategy, error) {
+	p := &exprParser{src: expr}
+	e, err := p.parseSum()
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", expr, err)
+	}
+	if p.skipSpaces(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("parse %q: unexpected %q at %d", expr, p.src[p.pos], p.pos)
+	}
+	return ExpressionPricing{expr: e}, nil
+}
+
+// exprParser is a recursive descent parser:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = factor { ("*" | "/") factor }
+//	factor  = number | "amount" | "(" sum ")" | "-" factor
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// accept consumes the next non-space byte if it is one of ops.
+func (p *exprParser) accept(ops string) (byte, bool) {
+	p.skipSpaces()
+	if p.pos < len(p.src) {
+		for i := 0; i < len(ops); i++ {
+			if p.src[p.pos] == ops[i] {
+				p.pos++
+				return ops[i], true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (p *exprParser) parseSum() (Expression, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
 }
 
+func (p *exprParser) parseProduct() (Expression, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseFactor() (Expression, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negateExpr{operand: operand}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		e, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("missing ')' at %d", p.pos)
+		}
+		return e, nil
+	}
+
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.' || isLetter(p.src[p.pos])) {
+		p.pos++
+	}
+	token := p.src[start:p.pos]
+	switch {
+	case token == "":
+		if p.pos < len(p.src) {
+			return nil, fmt.Errorf("unexpected %q at %d", p.src[p.pos], p.pos)
+		}
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "amount":
+		return amountExpr{}, nil
+	case isLetter(token[0]):
+		return nil, fmt.Errorf("unknown identifier %q", token)
+	}
+	n, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", token)
+	}
+	return numberExpr(n), nil
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+// ===== MIDDLEWARE =====
+// Cross-cutting behavior wrapped around PaymentService.ProcessPayment
+
+type ProcessFunc func(amount float64) error
+
+type Middleware func(next ProcessFunc) ProcessFunc
+
 // Context that uses both Factory and Strategy
 type PaymentService struct {
-	processor PaymentProcessor
-	strategy  PricingStrategy
+	processor   PaymentProcessor
+	strategy    PricingStrategy
+	middlewares []Middleware
+	approval    ApprovalHandler
 }
 
 func NewPaymentService(provider string, pricingStrategy PricingStrategy) (*PaymentService, error) {
@@ -83,16 +651,140 @@ func NewPaymentService(provider string, pricingStrategy PricingStrategy) (*Payme
 	}, nil
 }
 
+// Use registers middlewares. The first registered runs outermost.
+func (ps *PaymentService) Use(mw ...Middleware) {
+	ps.middlewares = append(ps.middlewares, mw...)
+}
+
 func (ps *PaymentService) ProcessPayment(amount float64) error {
+	_, err := ps.Charge(amount)
+	return err
+}
+
+// Charge is like ProcessPayment but also returns the amount actually sent
+// to the processor, after middlewares and pricing.
+func (ps *PaymentService) Charge(amount float64) (float64, error) {
+	var charged float64
+	handler := ProcessFunc(func(amount float64) error {
+		var err error
+		charged, err = ps.process(amount)
+		return err
+	})
+	for i := len(ps.middlewares) - 1; i >= 0; i-- {
+		handler = ps.middlewares[i](handler)
+	}
+	if err := handler(amount); err != nil {
+		return 0, err
+	}
+	return charged, nil
+}
+
+// SetApprovalChain sets the handlers every payment must pass before processing.
+func (ps *PaymentService) SetApprovalChain(chain ApprovalHandler) {
+	ps.approval = chain
+}
+
+// process approves, prices and charges amount, returning the final price.
+func (ps *PaymentService) process(amount float64) (float64, error) {
+	if ps.approval != nil {
+		if err := ps.approval.Approve(amount); err != nil {
+			return 0, fmt.Errorf("payment not approved: %w", err)
+		}
+	}
 	finalAmount := ps.strategy.CalculatePrice(amount)
 	fmt.Printf("Original: $%.2f, Final: $%.2f\n", amount, finalAmount)
-	return ps.processor.ProcessPayment(finalAmount)
+	if err := ps.processor.ProcessPayment(finalAmount); err != nil {
+		return 0, err
+	}
+	return finalAmount, nil
 }
 
 func (ps *PaymentService) SetPricingStrategy(strategy PricingStrategy) {
 	ps.strategy = strategy
 }
 
+// ===== FACADE PATTERN =====
+// One entry point that wires the service, charges and notifies
+
+// NotificationCommand matches the command in command_example.
+type NotificationCommand interface {
+	Execute(data string)
+}
+
+type EmailNotification struct{}
+
+func (e *EmailNotification) Execute(data string) {
+	fmt.Println("Email notification:", data)
+}
+
+type CheckoutFacade struct {
+	pricing PricingStrategy
+}
+
+func NewCheckoutFacade(pricing PricingStrategy) *CheckoutFacade {
+	return &CheckoutFacade{pricing: pricing}
+}
+
+// Checkout charges amount through provider and fires notify only when the
+// payment succeeds. notify may be nil.
+func (f *CheckoutFacade) Checkout(provider string, amount float64, notify NotificationCommand) error {
+	service, err := NewPaymentService(provider, f.pricing)
+	if err != nil {
+		return fmt.Errorf("checkout: %w", err)
+	}
+	if err := service.ProcessPayment(amount); err != nil {
+		return fmt.Errorf("checkout: %w", err)
+	}
+	if notify != nil {
+		notify.Execute(fmt.Sprintf("Payment of $%.2f via %s completed", amount, provider))
+	}
+	return nil
+}
+
+// ===== INVOICE AGGREGATION =====
+// Charges several services as one invoice and summarises the outcome
+
+// ServiceCharge is one line of an invoice. Charged is the amount the service
+// sent to its processor, and is zero when Err is set.
+type ServiceCharge struct {
+	Service *PaymentService
+	Amount  float64
+	Charged float64
+	Err     error
+}
+
+type InvoiceSummary struct {
+	TotalCharged float64
+	Breakdown    []ServiceCharge
+	Errors       []error
+}
+
+type InvoiceAggregator struct {
+	lines []ServiceCharge
+}
+
+func (a *InvoiceAggregator) Add(service *PaymentService, amount float64) {
+	a.lines = append(a.lines, ServiceCharge{Service: service, Amount: amount})
+}
+
+// Process charges every line in order. A failed line does not stop the rest,
+// so the summary can record a partial success.
+func (a *InvoiceAggregator) Process() InvoiceSummary {
+	var summary InvoiceSummary
+	for i, line := range a.lines {
+		charged, err := line.Service.Charge(line.Amount)
+		if err != nil {
+			line.Err = err
+			summary.Errors = append(summary.Errors, fmt.Errorf("invoice line %d: %w", i, err))
+		} else {
+			line.Charged = charged
+			summary.TotalCharged += charged
+		}
+		summary.Breakdown = append(summary.Breakdown, line)
+	}
+	return summary
+}
+
 func main() {
 	fmt.Println("=== FACTORY + STRATEGY PATTERN EXAMPLE ===")
 
@@ -114,4 +806,107 @@ func main() {
 	// Example 4: Switch pricing strategy at runtime
 	service2.SetPricingStrategy(StandardPricing{})
 	service2.ProcessPayment(100)
+
+	// Example 5: Middleware for logging and validation
+	service2.Use(
+		func(next ProcessFunc) ProcessFunc {
+			return func(amount float64) error {
+				fmt.Printf("[log] charging $%.2f\n", amount)
+				return next(amount)
+			}
+		},
+		func(next ProcessFunc) ProcessFunc {
+			return func(amount float64) error {
+				if amount < 0 {
+					return fmt.Errorf("invalid amount: %.2f", amount)
+				}
+				return next(amount)
+			}
+		},
+	)
+	service2.ProcessPayment(50)
+	if err := service2.ProcessPayment(-5); err != nil {
+		fmt.Println("Error:", err) // Error: invalid amount: -5.00
+	}
+
+	// Example 6: Approval chain for large payments
+	manager := &ManagerApproval{}
+	manager.SetNext(&DirectorApproval{})
+	service3, _ := NewPaymentService("paypal", StandardPricing{})
+	service3.SetApprovalChain(manager)
+	service3.ProcessPayment(500)   // no approval needed
+	service3.ProcessPayment(5000)  // manager
+	service3.ProcessPayment(20000) // manager + director
+	if err := service3.ProcessPayment(200000); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Example 7: Legacy processor behind an adapter
+	var legacy PaymentProcessor = NewLegacyProcessorAdapter(LegacyBankGateway{})
+	legacy.ProcessPayment(12.345) // [LegacyBank] Charging 1235 cents
+
+	// Example 8: Facade
+	checkout := NewCheckoutFacade(StandardPricing{})
+	checkout.Checkout("stripe", 40, &EmailNotification{})
+	if err := checkout.Checkout("bitcoin", 40, &EmailNotification{}); err != nil {
+		fmt.Println("Error:", err) // no notification is sent
+	}
+
+	// Example 9: Every strategy rounds to cents the same way
+	fmt.Println(StandardPricing{}.CalculatePrice(0.25))  // 0.26 (0.255)
+	fmt.Println(PremiumPricing{}.CalculatePrice(0.1))    // 0.11 (0.105)
+	fmt.Println(DiscountPricing{}.CalculatePrice(10.21)) // 10.01 (10.0058)
+
+	// Example 10: Retrying with the same idempotency key charges once
+	idempotent := NewIdempotentProcessor(StripeProcessor{})
+	idempotent.ProcessPaymentIdempotent("order-42", 30) // [Stripe] Processing $30.00
+	idempotent.ProcessPaymentIdempotent("order-42", 30) // cached, no charge
+
+	// Example 11: Compare what each strategy would charge
+	fmt.Println(ComparePricing(100, map[string]PricingStrategy{
+		"standard": StandardPricing{},
+		"premium":  PremiumPricing{},
+		"discount": DiscountPricing{},
+	})) // map[discount:98 premium:105 standard:102]
+
+	// Example 12: A/B test pricing with a 3:1 split
+	selector, _ := NewWeightedStrategySelector(
+		WeightedStrategy{Strategy: StandardPricing{}, Weight: 3},
+		WeightedStrategy{Strategy: PremiumPricing{}, Weight: 1},
+	)
+	for i := 0; i < 4; i++ {
+		fmt.Print(selector.Next().CalculatePrice(100), " ")
+	}
+	fmt.Println() // 102 102 102 105
+
+	// Example 13: Fee rules as expressions
+	fee, _ := NewExpressionPricing("amount * 1.02 + 5")
+	fmt.Println(fee.CalculatePrice(100)) // 107
+	if _, err := NewExpressionPricing("amount * (1.02 +"); err != nil {
+		fmt.Println("Error:", err) // Error: parse "amount * (1.02 +": unexpected end of expression
+	}
+
+	// Example 14: Circuit breaker around a failing provider
+	down := ProcessorFunc(func(amount float64) error { return errors.New("provider down") })
+	breaker := NewCircuitBreakerProcessor(down, 2, time.Minute)
+	fmt.Println(breaker.ProcessPayment(10)) // provider down
+	fmt.Println(breaker.ProcessPayment(10)) // provider down (circuit opens)
+	fmt.Println(breaker.ProcessPayment(10)) // circuit open: provider temporarily unavailable
+
+	// Example 15: One invoice across two services, one of which fails
+	invoice := &InvoiceAggregator{}
+	invoice.Add(service1, 100)    // premium pricing
+	invoice.Add(service3, 200000) // above the approval limit
+	summary := invoice.Process()
+	fmt.Println("Total charged:", summary.TotalCharged, "failed lines:", len(summary.Errors)) // Total charged: 105 failed lines: 1
+
+	// Example 16: Fee tiers loaded from JSON
+	tiered, _ := NewJSONTieredPricing([]byte(`[
+		{"min": 0, "max": 100, "rate": 1.05},
+		{"min": 100, "max": 1000, "rate": 1.02}
+	]`))
+	fmt.Println(tiered.CalculatePrice(50), tiered.CalculatePrice(500), tiered.CalculatePrice(5000)) // 52.5 510 5000
+	if _, err := NewJSONTieredPricing([]byte(`[{"min": 0, "max": 100, "rate": 1}, {"min": 50, "max": 200, "rate": 1}]`)); err != nil {
+		fmt.Println("Error:", err) // Error: tier 1: overlaps or precedes tier 0
+	}
 }
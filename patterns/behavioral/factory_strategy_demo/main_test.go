@@ -0,0 +1,627 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+func TestToCents(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   int
+	}{
+		{12.345, 1235},
+		{12.3449, 1234},
+		{1.0045, 100},
+		{1.005, 101},
+		{0.0049, 0},
+		{0.005, 1},
+		{0, 0},
+		{-12.345, -1235},
+		{-0.0049, 0},
+		{99999.995, 10000000},
+		{10000000.004, 1000000000},
+		{10000000.005, 1000000001},
+		{123456789.124, 12345678912},
+		{123456789.125, 12345678913},
+		{-10000000.005, -1000000001},
+	}
+	for _, tt := range tests {
+		if got := toCents(tt.amount); got != tt.want {
+			t.Errorf("toCents(%v) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestPricingRoundsOnceToCents(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy PricingStrategy
+		amount   float64
+		want     float64
+	}{
+		{"standard below half cent", StandardPricing{}, 0.24014, 0.24},
+		{"standard on half cent", StandardPricing{}, 0.25, 0.26},
+		{"premium on half cent", PremiumPricing{}, 0.1, 0.11},
+		{"discount", DiscountPricing{}, 10.21, 10.01},
+		{"large amount", StandardPricing{}, 10000000, 10200000},
+		{"negative is zero", StandardPricing{}, -5, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.strategy.CalculatePrice(tt.amount); got != tt.want {
+			t.Errorf("%s: CalculatePrice(%v) = %v, want %v", tt.name, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreakerCooldown(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	errDown := errors.New("provider down")
+	var fail bool
+	calls := 0
+	cb := NewCircuitBreakerProcessor(ProcessorFunc(func(float64) error {
+		calls++
+		if fail {
+			return errDown
+		}
+		return nil
+	}), 2, time.Minute)
+	cb.SetClock(fake)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		fail    bool
+		wantErr error
+	}{
+		{"first failure", 0, true, errDown},
+		{"second failure opens", 0, true, errDown},
+		{"open rejects", 0, true, ErrCircuitOpen},
+		{"still cooling down", 59 * time.Second, false, ErrCircuitOpen},
+		{"trial call fails, reopens", time.Second, true, errDown},
+		{"reopened", 0, false, ErrCircuitOpen},
+		{"trial call succeeds, closes", time.Minute, false, nil},
+		{"closed", 0, false, nil},
+	}
+	for _, s := range steps {
+		fake.Advance(s.advance)
+		fail = s.fail
+		if err := cb.ProcessPayment(1); !errors.Is(err, s.wantErr) {
+			t.Errorf("%s: err = %v, want %v", s.name, err, s.wantErr)
+		}
+	}
+	if calls != 5 {
+		t.Errorf("processor called %d times, want 5", calls)
+	}
+}
+
+// recordingProcessor remembers every amount it was asked to charge.
+type recordingProcessor struct {
+	charged []float64
+	err     error
+}
+
+func (r *recordingProcessor) ProcessPayment(amount float64) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.charged = append(r.charged, amount)
+	return nil
+}
+
+func TestInvoiceAggregatorPartialSuccess(t *testing.T) {
+	ok := &recordingProcessor{}
+	withCoupon := &PaymentService{processor: ok, strategy: StandardPricing{}}
+	withCoupon.Use(func(next ProcessFunc) ProcessFunc {
+		return func(amount float64) error { return next(amount - 10) } // $10 coupon
+	})
+	errDeclined := errors.New("card declined")
+	failing := &PaymentService{processor: &recordingProcessor{err: errDeclined}, strategy: StandardPricing{}}
+
+	invoice := &InvoiceAggregator{}
+	invoice.Add(withCoupon, 110)
+	invoice.Add(failing, 50)
+	summary := invoice.Process()
+
+	if summary.TotalCharged != 102 {
+		t.Errorf("TotalCharged = %v, want 102", summary.TotalCharged)
+	}
+	if len(ok.charged) != 1 || ok.charged[0] != 102 {
+		t.Errorf("processor charged %v, want [102]", ok.charged)
+	}
+	if len(summary.Errors) != 1 || !errors.Is(summary.Errors[0], errDeclined) {
+		t.Errorf("Errors = %v, want one wrapping %v", summary.Errors, errDeclined)
+	}
+	want := []struct {
+		charged float64
+		failed  bool
+	}{{102, false}, {0, true}}
+	for i, w := range want {
+		line := summary.Breakdown[i]
+		if line.Charged != w.charged || (line.Err != nil) != w.failed {
+			t.Errorf("line %d = %+v, want charged %v failed %v", i, line, w.charged, w.failed)
+		}
+	}
+}
+
+func TestIdempotentProcessorConcurrentKeys(t *testing.T) {
+	var charges atomic.Int32
+	release := make(chan struct{})
+	slow := ProcessorFunc(func(amount float64) error {
+		charges.Add(1)
+		if amount == 1 {
+			<-release // "order-1" hangs until released
+		}
+		return nil
+	})
+	ip := NewIdempotentProcessor(slow)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ip.ProcessPaymentIdempotent("order-1", 1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	other := make(chan error)
+	go func() { other <- ip.ProcessPaymentIdempotent("order-2", 2) }()
+	select {
+	case err := <-other:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("order-2 waited for order-1's charge")
+	}
+
+	close(release)
+	wg.Wait()
+	if got := charges.Load(); got != 2 {
+		t.Errorf("charged %d times, want 2", got)
+	}
+}
+
+func TestIdempotentProcessorRetriesFailures(t *testing.T) {
+	errDeclined := errors.New("declined")
+	results := []error{errDeclined, errDeclined, nil}
+	calls := 0
+	ip := NewIdempotentProcessor(ProcessorFunc(func(float64) error {
+		err := results[calls]
+		calls++
+		return err
+	}))
+	want := []error{errDeclined, errDeclined, nil, nil}
+	for i, w := range want {
+		if err := ip.ProcessPaymentIdempotent("order-9", 5); !errors.Is(err, w) {
+			t.Errorf("call %d: err = %v, want %v", i, err, w)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("processor called %d times, want 3", calls)
+	}
+}
+
+func TestIdempotentProcessorPanicReleasesWaiters(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	ip := NewIdempotentProcessor(ProcessorFunc(func(float64) error {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+			panic("gateway crashed")
+		}
+		return nil
+	}))
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		ip.ProcessPaymentIdempotent("order-7", 5)
+	}()
+	<-started
+	waiter := make(chan error)
+	go func() { waiter <- ip.ProcessPaymentIdempotent("order-7", 5) }()
+	close(release)
+
+	if r := <-panicked; r == nil {
+		t.Error("the processor's panic was swallowed")
+	}
+	select {
+	case err := <-waiter:
+		// The waiter either saw the aborted charge or arrived after it and
+		// charged again.
+		if err != nil && !errors.Is(err, ErrChargeAborted) {
+			t.Errorf("waiter err = %v, want nil or ErrChargeAborted", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter still blocked after the processor panicked")
+	}
+	if err := ip.ProcessPaymentIdempotent("order-7", 5); err != nil {
+		t.Errorf("retry after the panic = %v, want nil", err)
+	}
+}
+
+func TestIdempotentProcessor(t *testing.T) {
+	rec := &recordingProcessor{}
+	ip := NewIdempotentProcessor(rec)
+	calls := []struct {
+		key    string
+		amount float64
+	}{
+		{"order-1", 10},
+		{"order-1", 10}, // retry
+		{"order-2", 20},
+		{"order-1", 99}, // same key, different amount: still not charged
+	}
+	for _, c := range calls {
+		if err := ip.ProcessPaymentIdempotent(c.key, c.amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []float64{10, 20}; !slices.Equal(rec.charged, want) {
+		t.Errorf("charged %v, want %v", rec.charged, want)
+	}
+}
+
+// legacyRecorder is a LegacyProcessor that remembers the cents it was charged.
+type legacyRecorder struct {
+	cents []int
+}
+
+func (l *legacyRecorder) Charge(cents int) error {
+	l.cents = append(l.cents, cents)
+	return nil
+}
+
+func TestLegacyProcessorAdapter(t *testing.T) {
+	legacy := &legacyRecorder{}
+	var p PaymentProcessor = NewLegacyProcessorAdapter(legacy)
+	for _, amount := range []float64{10, 0.1 + 0.2, 12.345, 0} {
+		if err := p.ProcessPayment(amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []int{1000, 30, 1235, 0}; !slices.Equal(legacy.cents, want) {
+		t.Errorf("charged %v cents, want %v", legacy.cents, want)
+	}
+}
+
+func TestComparePricing(t *testing.T) {
+	got := ComparePricing(100, map[string]PricingStrategy{
+		"standard": StandardPricing{},
+		"premium":  PremiumPricing{},
+		"discount": DiscountPricing{},
+	})
+	want := map[string]float64{"standard": 102, "premium": 105, "discount": 98}
+	if !maps.Equal(got, want) {
+		t.Errorf("ComparePricing() = %v, want %v", got, want)
+	}
+	if got := ComparePricing(100, nil); len(got) != 0 {
+		t.Errorf("ComparePricing(nil) = %v", got)
+	}
+}
+
+func TestBasePricing(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy PricingStrategy
+		amount   float64
+		want     float64
+	}{
+		{"standard", StandardPricing{}, 100, 102},
+		{"premium", PremiumPricing{}, 10, 10.5},
+		{"discount", DiscountPricing{}, 50, 49},
+		{"negative is zero", StandardPricing{}, -5, 0},
+		{"zero", PremiumPricing{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.strategy.CalculatePrice(tt.amount); got != tt.want {
+				t.Errorf("CalculatePrice(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+	if got := (BasePricing{}).Compute(12.5); got != 12.5 {
+		t.Errorf("Compute without a hook = %v, want 12.5", got)
+	}
+}
+
+func TestWeightedStrategySelector(t *testing.T) {
+	standard, premium := StandardPricing{}, PremiumPricing{}
+	w, err := NewWeightedStrategySelector(
+		WeightedStrategy{Strategy: standard, Weight: 3},
+		WeightedStrategy{Strategy: premium, Weight: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []PricingStrategy
+	for i := 0; i < 8; i++ {
+		got = append(got, w.Next())
+	}
+	want := []PricingStrategy{standard, standard, standard, premium, standard, standard, standard, premium}
+	if !slices.Equal(got, want) {
+		t.Errorf("Next() sequence = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedStrategySelectorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []WeightedStrategy
+	}{
+		{"no entries", nil},
+		{"zero weight", []WeightedStrategy{{StandardPricing{}, 1}, {PremiumPricing{}, 0}}},
+		{"negative weight", []WeightedStrategy{{StandardPricing{}, -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, err := NewWeightedStrategySelector(tt.entries...); err == nil || w != nil {
+				t.Errorf("NewWeightedStrategySelector() = %v, %v; want an error", w, err)
+			}
+		})
+	}
+}
+
+func TestWeightedStrategySelectorConcurrent(t *testing.T) {
+	w, _ := NewWeightedStrategySelector(
+		WeightedStrategy{Strategy: StandardPricing{}, Weight: 2},
+		WeightedStrategy{Strategy: PremiumPricing{}, Weight: 1},
+	)
+	var (
+		mu     sync.Mutex
+		counts = map[PricingStrategy]int{}
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := w.Next()
+			mu.Lock()
+			counts[s]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if counts[StandardPricing{}] != 20 || counts[PremiumPricing{}] != 10 {
+		t.Errorf("counts = %v, want 20 standard and 10 premium", counts)
+	}
+}
+
+func TestJSONTieredPricing(t *testing.T) {
+	p, err := NewJSONTieredPricing([]byte(`[
+		{"min": 0, "max": 100, "rate": 1.05},
+		{"min": 100, "max": 1000, "rate": 1.02}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		amount, want float64
+	}{
+		{50, 52.5},
+		{99.99, 104.99},
+		{100, 102}, // max is exclusive
+		{1000, 1000},
+		{20000000, 20000000},
+		{-10, 0},
+	}
+	for _, tt := range tests {
+		if got := p.CalculatePrice(tt.amount); got != tt.want {
+			t.Errorf("CalculatePrice(%v) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestNewJSONTieredPricingErrors(t *testing.T) {
+	tests := []struct {
+		name, data, wantErr string
+	}{
+		{"malformed", `[{"min": 0,`, "tiers:"},
+		{"not a list", `{"min": 0}`, "tiers:"},
+		{"empty range", `[{"min": 10, "max": 10, "rate": 1}]`, "tier 0: min"},
+		{"negative rate", `[{"min": 0, "max": 10, "rate": -1}]`, "tier 0: invalid rate"},
+		{"overlap", `[{"min": 0, "max": 10, "rate": 1}, {"min": 5, "max": 20, "rate": 1}]`, "tier 1: overlaps"},
+		{"out of order", `[{"min": 10, "max": 20, "rate": 1}, {"min": 0, "max": 5, "rate": 1}]`, "tier 1: overlaps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJSONTieredPricing([]byte(tt.data))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want prefix %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := NewJSONTieredPricing([]byte(`[]`)); err != nil {
+		t.Errorf("no tiers: %v", err)
+	}
+}
+
+func TestApprovalChain(t *testing.T) {
+	tests := []struct {
+		amount  float64
+		wantErr bool
+	}{
+		{500, false},
+		{5000, false},
+		{maxApprovableAmount, false},
+		{maxApprovableAmount + 0.01, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.amount), func(t *testing.T) {
+			manager := &ManagerApproval{}
+			manager.SetNext(&DirectorApproval{})
+			if err := manager.Approve(tt.amount); (err != nil) != tt.wantErr {
+				t.Errorf("Approve(%v) = %v, want error %v", tt.amount, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewExpressionPricing(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64 // price for amount = 100
+	}{
+		{"amount", 100},
+		{"amount * 1.02 + 5", 107},
+		{"5 + amount * 2", 205},
+		{"(5 + amount) * 2", 210},
+		{"amount - 10 - 5", 85}, // left associative
+		{"amount / 4 / 5", 5},
+		{"-amount + 150", 50},
+		{"--amount", 100},
+		{"  amount*2  ", 200},
+		{"42", 42},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := NewExpressionPricing(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.CalculatePrice(100); got != tt.want {
+				t.Errorf("CalculatePrice(100) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewExpressionPricingErrors(t *testing.T) {
+	tests := []struct {
+		expr, wantErr string
+	}{
+		{"", "unexpected end of expression"},
+		{"amount +", "unexpected end of expression"},
+		{"(amount", "missing ')'"},
+		{"amount)", `unexpected ')' at 6`},
+		{"price * 2", `unknown identifier "price"`},
+		{"1.2.3", `invalid number "1.2.3"`},
+		{"amount % 2", `unexpected '%' at 7`},
+		{"* 2", `unexpected '*' at 0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := NewExpressionPricing(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || p != nil {
+				t.Errorf("NewExpressionPricing(%q) = %v, %v; want error containing %q", tt.expr, p, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceMiddlewareOrder(t *testing.T) {
+	rec := &recordingProcessor{}
+	ps := &PaymentService{processor: rec, strategy: StandardPricing{}}
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next ProcessFunc) ProcessFunc {
+			return func(amount float64) error {
+				order = append(order, name)
+				return next(amount)
+			}
+		}
+	}
+	ps.Use(tag("first"), tag("second"))
+	ps.Use(tag("third"))
+	if err := ps.ProcessPayment(100); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second", "third"}; !slices.Equal(order, want) {
+		t.Errorf("middleware order = %v, want %v", order, want)
+	}
+
+	errBlocked := errors.New("blocked")
+	ps.Use(func(ProcessFunc) ProcessFunc {
+		return func(float64) error { return errBlocked }
+	})
+	charged, err := ps.Charge(100)
+	if !errors.Is(err, errBlocked) || charged != 0 {
+		t.Errorf("Charge() = %v, %v; want 0, %v", charged, err, errBlocked)
+	}
+	if !slices.Equal(rec.charged, []float64{102}) {
+		t.Errorf("processor charged %v, want [102]", rec.charged)
+	}
+}
+
+func TestPaymentServiceApproval(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      float64
+		wantErr     bool
+		wantCharged []float64
+	}{
+		{"approved", 2000, false, []float64{2040}},
+		{"rejected", maxApprovableAmount * 2, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingProcessor{}
+			ps := &PaymentService{processor: rec, strategy: StandardPricing{}}
+			chain := &ManagerApproval{}
+			chain.SetNext(&DirectorApproval{})
+			ps.SetApprovalChain(chain)
+			err := ps.ProcessPayment(tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessPayment() = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "payment not approved:") {
+				t.Errorf("error = %q", err)
+			}
+			if !slices.Equal(rec.charged, tt.wantCharged) {
+				t.Errorf("charged %v, want %v", rec.charged, tt.wantCharged)
+			}
+		})
+	}
+}
+
+// notifyRecorder is a NotificationCommand that remembers what it was sent.
+type notifyRecorder struct {
+	sent []string
+}
+
+func (n *notifyRecorder) Execute(data string) { n.sent = append(n.sent, data) }
+
+func TestCheckoutFacade(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		notify    bool
+		wantErr   bool
+		wantNotes int
+	}{
+		{"notifies on success", "paypal", true, false, 1},
+		{"nil notifier", "stripe", false, false, 0},
+		{"unknown provider", "cash", true, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &notifyRecorder{}
+			var notify NotificationCommand
+			if tt.notify {
+				notify = rec
+			}
+			err := NewCheckoutFacade(StandardPricing{}).Checkout(tt.provider, 100, notify)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Checkout() = %v, want error %v", err, tt.wantErr)
+			}
+			if len(rec.sent) != tt.wantNotes {
+				t.Errorf("notifications = %v, want %d", rec.sent, tt.wantNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+// NotificationCommand matches the command in command_example, so commands
+// and subscribers can be registered in each other's registries.
+type NotificationCommand interface {
+	Execute(data string)
+}
+
+// Adapter - a command registered as a Subscriber
+type commandSubscriber struct {
+	cmd NotificationCommand
+}
+
+func (c commandSubscriber) Update(article string) error {
+	c.cmd.Execute(article)
+	return nil
+}
+
+// SubscriberFromCommand lets a Publisher notify cmd. Commands cannot fail, so
+// the returned Subscriber always acks.
+func SubscriberFromCommand(cmd NotificationCommand) Subscriber {
+	return commandSubscriber{cmd: cmd}
+}
+
+// Adapter - a subscriber registered as a NotificationCommand
+type subscriberCommand struct {
+	sub Subscriber
+}
+
+func (s subscriberCommand) Execute(data string) {
+	if err := s.sub.Update(data); err != nil {
+		fmt.Println("Notification failed:", err)
+	}
+}
+
+// CommandFromSubscriber lets a NotificationCenter run sub. Update errors are
+// reported, since Execute has no way to return them.
+func CommandFromSubscriber(sub Subscriber) NotificationCommand {
+	return subscriberCommand{sub: sub}
+}
+
+// Concrete Command
+type LogCommand struct {
+	Prefix string
+}
+
+func (l *LogCommand) Execute(data string) {
+	fmt.Printf("%s %s\n", l.Prefix, data)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// commandRecorder is a NotificationCommand that remembers what it ran.
+type commandRecorder struct {
+	got []string
+}
+
+func (c *commandRecorder) Execute(data string) { c.got = append(c.got, data) }
+
+func TestSubscriberFromCommand(t *testing.T) {
+	cmd := &commandRecorder{}
+	p := &Publisher{}
+	p.Register(SubscriberFromCommand(cmd))
+	acks := p.NotifyWithAck("x")
+	for _, err := range acks {
+		if err != nil {
+			t.Errorf("command subscriber nacked: %v", err)
+		}
+	}
+	if !slices.Equal(cmd.got, []string{"x"}) {
+		t.Errorf("command ran with %v", cmd.got)
+	}
+}
+
+func TestCommandFromSubscriber(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"success", nil},
+		{"failure is reported, not returned", errors.New("down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &safeRecorder{err: tt.err}
+			CommandFromSubscriber(sub).Execute("x")
+			if got := sub.articles(); !slices.Equal(got, []string{"x"}) {
+				t.Errorf("subscriber got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// Event is a typed notification carrying an arbitrary payload.
+type Event struct {
+	Name      string
+	Payload   any
+	Timestamp time.Time
+}
+
+// Observer interface for the EventBus
+type EventSubscriber interface {
+	OnEvent(event Event)
+}
+
+// Concrete Observer
+type AuditSubscriber struct {
+	Name string
+}
+
+func (a *AuditSubscriber) OnEvent(event Event) {
+	fmt.Printf("Audit %s: %s %v\n", a.Name, event.Name, event.Payload)
+}
+
+// Subject - dispatches events to the subscribers of each event name.
+// It is safe for concurrent use.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]EventSubscriber
+	clk         clock.Clock // stamps published events; nil means clock.System
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[string][]EventSubscriber)}
+}
+
+// SetClock replaces the clock that stamps published events. The default is
+// clock.System.
+func (b *EventBus) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clk = c
+}
+
+func (b *EventBus) Subscribe(name string, sub EventSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[name] = append(b.subscribers[name], sub)
+}
+
+// Publish delivers event to the subscribers registered for event.Name.
+// A zero Timestamp is set to the current time. Subscribers are called
+// without holding the lock, so they may subscribe or publish themselves.
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	subs := slices.Clone(b.subscribers[event.Name])
+	clk := clock.Or(b.clk)
+	b.mu.RUnlock()
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = clk.Now()
+	}
+	for _, sub := range subs {
+		sub.OnEvent(event)
+	}
+}
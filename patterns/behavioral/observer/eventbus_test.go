@@ -0,0 +1,121 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// eventRecorder remembers the events it receives.
+type eventRecorder struct {
+	events []Event
+}
+
+func (r *eventRecorder) OnEvent(e Event) { r.events = append(r.events, e) }
+
+func TestEventBusRoutesByName(t *testing.T) {
+	bus := NewEventBus()
+	published, deleted := &eventRecorder{}, &eventRecorder{}
+	bus.Subscribe("article.published", published)
+	bus.Subscribe("article.published", published)
+	bus.Subscribe("article.deleted", deleted)
+
+	tests := []struct {
+		name                   string
+		wantPublished, wantDel int
+	}{
+		{"article.published", 2, 0},
+		{"article.deleted", 2, 1},
+		{"article.unknown", 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus.Publish(Event{Name: tt.name, Payload: 42})
+			if len(published.events) != tt.wantPublished || len(deleted.events) != tt.wantDel {
+				t.Errorf("published got %d, deleted got %d; want %d and %d",
+					len(published.events), len(deleted.events), tt.wantPublished, tt.wantDel)
+			}
+		})
+	}
+	if got := published.events[0].Payload; got != 42 {
+		t.Errorf("payload = %v, want 42", got)
+	}
+}
+
+func TestEventBusTimestamp(t *testing.T) {
+	bus := NewEventBus()
+	rec := &eventRecorder{}
+	bus.Subscribe("e", rec)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	bus.SetClock(clock.NewFake(now))
+	fixed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bus.Publish(Event{Name: "e"})
+	bus.Publish(Event{Name: "e", Timestamp: fixed})
+	if ts := rec.events[0].Timestamp; !ts.Equal(now) {
+		t.Errorf("zero timestamp set to %v, want %v", ts, now)
+	}
+	if ts := rec.events[1].Timestamp; !ts.Equal(fixed) {
+		t.Errorf("timestamp = %v, want %v kept", ts, fixed)
+	}
+}
+
+// eventCounter counts events and is safe for concurrent use.
+type eventCounter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *eventCounter) OnEvent(Event) {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func TestEventBusConcurrentSubscribeAndPublish(t *testing.T) {
+	bus := NewEventBus()
+	first := &eventCounter{}
+	bus.Subscribe("e", first)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bus.Subscribe("e", &eventCounter{})
+		}()
+		go func() {
+			defer wg.Done()
+			bus.Publish(Event{Name: "e"})
+		}()
+	}
+	wg.Wait()
+	if first.n != 8 {
+		t.Errorf("first subscriber got %d events, want 8", first.n)
+	}
+}
+
+// resubscriber subscribes another recorder from inside OnEvent.
+type resubscriber struct {
+	bus *EventBus
+	rec *eventRecorder
+}
+
+func (r *resubscriber) OnEvent(e Event) { r.bus.Subscribe(e.Name, r.rec) }
+
+func TestEventBusSubscribeFromHandler(t *testing.T) {
+	bus := NewEventBus()
+	rec := &eventRecorder{}
+	bus.Subscribe("e", &resubscriber{bus, rec})
+
+	bus.Publish(Event{Name: "e"}) // must not deadlock
+	if len(rec.events) != 0 {
+		t.Errorf("subscriber added during Publish got %d events, want 0", len(rec.events))
+	}
+	bus.Publish(Event{Name: "e"})
+	if len(rec.events) != 1 {
+		t.Errorf("subscriber got %d events after the next Publish, want 1", len(rec.events))
+	}
+}
@@ -1,10 +1,22 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
-// Observer interface
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+var ErrPublisherClosed = errors.New("publisher is closed")
+
+// Observer interface. The Publisher keys its bookkeeping by Subscriber, so
+// implementations must be comparable, such as pointers to structs.
 type Subscriber interface {
-	Update(article string)
+	Update(article string) error
 }
 
 // Concrete Observer
@@ -12,8 +24,9 @@ type EmailSubscriber struct {
 	Email string
 }
 
-func (e *EmailSubscriber) Update(article string) {
+func (e *EmailSubscriber) Update(article string) error {
 	fmt.Printf("Email to %s: New article published: %s\n", e.Email, article)
+	return nil
 }
 
 // Concrete Observer
@@ -21,32 +34,336 @@ type SmsSubscriber struct {
 	Phone string
 }
 
-func (s *SmsSubscriber) Update(article string) {
+func (s *SmsSubscriber) Update(article string) error {
 	fmt.Printf("SMS to %s: New article published: %s\n", s.Phone, article)
+	return nil
+}
+
+// Concrete Observer
+type WebhookSubscriber struct {
+	URL string
+}
+
+func (w *WebhookSubscriber) Update(article string) error {
+	if w.URL == "" {
+		return errors.New("webhook: no URL configured")
+	}
+	fmt.Printf("POST %s: New article published: %s\n", w.URL, article)
+	return nil
+}
+
+// Concrete Observer - buffers articles and forwards them in batches
+type BatchingSubscriber struct {
+	flushMu  sync.Mutex // serializes deliver so batches arrive one at a time, in order
+	mu       sync.Mutex
+	size     int
+	interval time.Duration
+	deliver  func(batch []string)
+	buf      []string
+	timer    *time.Timer
+}
+
+// NewBatchingSubscriber forwards buffered articles to deliver once size of
+// them are waiting or interval has passed since the first one arrived. An
+// interval <= 0 disables the time-based flush.
+func NewBatchingSubscriber(size int, interval time.Duration, deliver func(batch []string)) *BatchingSubscriber {
+	return &BatchingSubscriber{size: size, interval: interval, deliver: deliver}
+}
+
+func (b *BatchingSubscriber) Update(article string) error {
+	b.mu.Lock()
+	b.buf = append(b.buf, article)
+	full := len(b.buf) >= b.size
+	if !full && b.timer == nil && b.interval > 0 {
+		b.timer = time.AfterFunc(b.interval, b.Flush)
+	}
+	b.mu.Unlock()
+	if full {
+		b.Flush()
+	}
+	return nil
+}
+
+// Flush delivers whatever is buffered, if anything. Flushes from the timer
+// and from Update never overlap: deliver sees batches one at a time, in the
+// order the articles arrived. deliver must not call Flush itself.
+func (b *BatchingSubscriber) Flush() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.mu.Lock()
+	batch := b.buf
+	b.buf = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+	if len(batch) > 0 {
+		b.deliver(batch)
+	}
+}
+
+// Metrics counts published articles and, per subscriber, successful and
+// failed deliveries.
+type Metrics struct {
+	TotalNotifications      int
+	DeliveriesPerSubscriber map[Subscriber]int
+	FailuresPerSubscriber   map[Subscriber]int
 }
 
 // Subject (Publisher)
 type Publisher struct {
 	subscribers []Subscriber
+
+	metricsMu sync.Mutex
+	metrics   Metrics
+
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+	lastSeq  map[Subscriber]int // highest seq delivered by NotifySeq
+
+	maxFailures int                // 0 disables auto-unregister
+	failures    map[Subscriber]int // consecutive failed deliveries
+
+	clk clock.Clock // paces NotifyRateLimited; nil means clock.System
+}
+
+// SetClock replaces the clock that paces NotifyRateLimited. The default is
+// clock.System.
+func (p *Publisher) SetClock(c clock.Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clk = c
 }
 
-func (p *Publisher) Register(sub Subscriber) {
+// Register adds sub and returns a func that unregisters it. Calling the func
+// more than once has no further effect.
+func (p *Publisher) Register(sub Subscriber) (unsubscribe func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.subscribers = append(p.subscribers, sub)
+	var once sync.Once
+	return func() {
+		once.Do(func() { p.Unregister(sub) })
+	}
 }
+
 func (p *Publisher) Unregister(sub Subscriber) {
-	for i, s := range p.subscribers {
-		if s == sub {
-			p.subscribers = append(p.subscribers[:i], p.subscribers[i+1:]...)
-			break
-		}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(sub)
+}
+
+// snapshot returns the current subscribers. The slice is never modified in
+// place, so it is safe to range over without holding p.mu.
+func (p *Publisher) snapshot() []Subscriber {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.subscribers
+}
+
+// removeLocked drops the first registration of sub without modifying the old
+// slice in place, so loops already ranging over it are unaffected. p.mu must
+// be held.
+func (p *Publisher) removeLocked(sub Subscriber) {
+	i := slices.Index(p.subscribers, sub)
+	if i < 0 {
+		return
+	}
+	p.subscribers = slices.Delete(slices.Clone(p.subscribers), i, i+1)
+	if !slices.Contains(p.subscribers, sub) {
+		delete(p.failures, sub)
+		delete(p.lastSeq, sub)
 	}
 }
+
+// SetMaxFailures makes the publisher unregister a subscriber after n
+// consecutive failed deliveries. A success resets the count; n <= 0 disables
+// the policy.
+func (p *Publisher) SetMaxFailures(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxFailures = n
+}
 func (p *Publisher) Notify(article string) {
-	for _, sub := range p.subscribers {
-		sub.Update(article)
+	p.recordNotification()
+	for _, sub := range p.snapshot() {
+		p.deliver(sub, article)
+	}
+}
+
+// NotifyWithAck delivers article and returns each subscriber's result:
+// nil is an ack, an error is a nack.
+func (p *Publisher) NotifyWithAck(article string) map[Subscriber]error {
+	p.recordNotification()
+	subs := p.snapshot()
+	results := make(map[Subscriber]error, len(subs))
+	for _, sub := range subs {
+		results[sub] = p.deliver(sub, article)
+	}
+	return results
+}
+
+// NotifySeq delivers article to each subscriber that has not yet been sent a
+// seq at least as high, so out-of-order replays are dropped. A failed
+// delivery does not advance the subscriber's seq.
+func (p *Publisher) NotifySeq(seq int, article string) {
+	p.recordNotification()
+	for _, sub := range p.snapshot() {
+		p.mu.Lock()
+		last, seen := p.lastSeq[sub]
+		p.mu.Unlock()
+		if seen && seq <= last {
+			continue
+		}
+		if p.deliver(sub, article) != nil {
+			continue
+		}
+		p.mu.Lock()
+		if p.lastSeq == nil {
+			p.lastSeq = make(map[Subscriber]int)
+		}
+		p.lastSeq[sub] = seq
+		p.mu.Unlock()
+	}
+}
+
+// deliver updates sub and records the outcome.
+func (p *Publisher) deliver(sub Subscriber, article string) error {
+	err := sub.Update(article)
+	p.recordHealth(sub, err)
+	p.recordDelivery(sub, err)
+	return err
+}
+
+func (p *Publisher) recordHealth(sub Subscriber, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		delete(p.failures, sub)
+		return
+	}
+	if p.maxFailures <= 0 {
+		return
+	}
+	if p.failures == nil {
+		p.failures = make(map[Subscriber]int)
+	}
+	p.failures[sub]++
+	if p.failures[sub] >= p.maxFailures {
+		p.removeLocked(sub)
 	}
 }
 
+// NotifyRateLimited delivers article to every subscriber, at most perSecond
+// deliveries per second. perSecond <= 0 falls back to Notify. Rates above
+// one per nanosecond are paced at one per nanosecond.
+func (p *Publisher) NotifyRateLimited(article string, perSecond int) {
+	if perSecond <= 0 {
+		p.Notify(article)
+		return
+	}
+
+	p.recordNotification()
+	p.mu.Lock()
+	clk := clock.Or(p.clk)
+	p.mu.Unlock()
+	interval := max(time.Second/time.Duration(perSecond), time.Nanosecond)
+	for i, sub := range p.snapshot() {
+		if i > 0 {
+			<-clk.After(interval)
+		}
+		p.deliver(sub, article)
+	}
+}
+
+// NotifyAsync delivers article in the background. It fails with
+// ErrPublisherClosed once Close has been called.
+func (p *Publisher) NotifyAsync(article string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	p.inFlight.Add(1)
+	go func() {
+		defer p.inFlight.Done()
+		p.Notify(article)
+	}()
+	return nil
+}
+
+// Close stops accepting async notifications and waits for the in-flight
+// ones to finish.
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.inFlight.Wait()
+}
+
+func (p *Publisher) recordNotification() {
+	p.metricsMu.Lock()
+	defer p.metricsMu.Unlock()
+	p.metrics.TotalNotifications++
+}
+
+func (p *Publisher) recordDelivery(sub Subscriber, err error) {
+	p.metricsMu.Lock()
+	defer p.metricsMu.Unlock()
+	if err != nil {
+		if p.metrics.FailuresPerSubscriber == nil {
+			p.metrics.FailuresPerSubscriber = make(map[Subscriber]int)
+		}
+		p.metrics.FailuresPerSubscriber[sub]++
+		return
+	}
+	if p.metrics.DeliveriesPerSubscriber == nil {
+		p.metrics.DeliveriesPerSubscriber = make(map[Subscriber]int)
+	}
+	p.metrics.DeliveriesPerSubscriber[sub]++
+}
+
+// Snapshot returns a copy of the current metrics.
+func (p *Publisher) Snapshot() Metrics {
+	p.metricsMu.Lock()
+	defer p.metricsMu.Unlock()
+	return Metrics{
+		TotalNotifications:      p.metrics.TotalNotifications,
+		DeliveriesPerSubscriber: maps.Clone(p.metrics.DeliveriesPerSubscriber),
+		FailuresPerSubscriber:   maps.Clone(p.metrics.FailuresPerSubscriber),
+	}
+}
+
+// NotifyPool delivers article using a fixed number of worker goroutines and
+// returns once every subscriber has been updated. workers <= 0 falls back to
+// the synchronous Notify.
+func (p *Publisher) NotifyPool(article string, workers int) {
+	if workers <= 0 {
+		p.Notify(article)
+		return
+	}
+
+	p.recordNotification()
+	jobs := make(chan Subscriber)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range jobs {
+				p.deliver(sub, article)
+			}
+		}()
+	}
+	for _, sub := range p.snapshot() {
+		jobs <- sub
+	}
+	close(jobs)
+	wg.Wait()
+}
+
 func main() {
 	publisher := &Publisher{}
 
@@ -65,4 +382,79 @@ func main() {
 	publisher.Notify("Another Article")
 	// Output:
 	// SMS to +1234567890: New article published: Another Article
+
+	publisher.Register(emailSub)
+	publisher.NotifyPool("Worker Pools in Go", 4)
+	// Output (order may vary):
+	// SMS to +1234567890: New article published: Worker Pools in Go
+	// Email to alice@example.com: New article published: Worker Pools in Go
+
+	bus := NewEventBus()
+	bus.Subscribe("article.published", &AuditSubscriber{Name: "editor"})
+	bus.Publish(Event{Name: "article.published", Payload: "Typed Events"})
+	bus.Publish(Event{Name: "article.deleted", Payload: "Old Post"})
+	// Output:
+	// Audit editor: article.published Typed Events
+
+	metrics := publisher.Snapshot()
+	fmt.Println("Articles:", metrics.TotalNotifications)                        // Articles: 3
+	fmt.Println("SMS deliveries:", metrics.DeliveriesPerSubscriber[smsSub])     // SMS deliveries: 3
+	fmt.Println("Email deliveries:", metrics.DeliveriesPerSubscriber[emailSub]) // Email deliveries: 2
+
+	publisher.NotifyAsync("Graceful Shutdown")
+	publisher.Close() // waits for the delivery above
+	if err := publisher.NotifyAsync("Too Late"); err != nil {
+		fmt.Println("Error:", err) // Error: publisher is closed
+	}
+
+	publisher.NotifyRateLimited("Paced Delivery", 10) // one delivery every 100ms
+
+	broken := &WebhookSubscriber{}
+	publisher.Register(broken)
+	acks := publisher.NotifyWithAck("Acknowledgements")
+	fmt.Println("SMS ack:", acks[smsSub], "webhook ack:", acks[broken]) // SMS ack: <nil> webhook ack: webhook: no URL configured
+
+	ordered := &Publisher{}
+	ordered.Register(&SmsSubscriber{Phone: "+1987654321"})
+	ordered.NotifySeq(1, "Part 1") // SMS to +1987654321: New article published: Part 1
+	ordered.NotifySeq(3, "Part 3") // SMS to +1987654321: New article published: Part 3
+	ordered.NotifySeq(2, "Part 2") // (skipped: seq 3 already delivered)
+
+	monitored := &Publisher{}
+	monitored.SetMaxFailures(2)
+	flaky := &WebhookSubscriber{}
+	monitored.Register(flaky)
+	monitored.Register(&SmsSubscriber{Phone: "+1555000111"})
+	monitored.Notify("Health 1")
+	monitored.Notify("Health 2")                                 // the webhook fails a second time and is dropped
+	fmt.Println("Subscribers left:", len(monitored.subscribers)) // Subscribers left: 1
+
+	scoped := &Publisher{}
+	unsubscribe := scoped.Register(&EmailSubscriber{Email: "bob@example.com"})
+	scoped.Notify("Scoped 1") // Email to bob@example.com: New article published: Scoped 1
+	unsubscribe()
+	unsubscribe()
+	scoped.Notify("Scoped 2")                                // no output
+	fmt.Println("Subscribers left:", len(scoped.snapshot())) // Subscribers left: 0
+
+	bridged := &Publisher{}
+	bridged.Register(SubscriberFromCommand(&LogCommand{Prefix: "[log]"}))
+	bridged.Notify("Bridged Article") // [log] Bridged Article
+	CommandFromSubscriber(&SmsSubscriber{Phone: "+1555000222"}).Execute("Via Command")
+	// SMS to +1555000222: New article published: Via Command
+	CommandFromSubscriber(&WebhookSubscriber{}).Execute("Via Command")
+	// Notification failed: webhook: no URL configured
+
+	batched := &Publisher{}
+	batcher := NewBatchingSubscriber(3, time.Minute, func(batch []string) {
+		fmt.Println("Batch:", batch)
+	})
+	batched.Register(batcher)
+	for _, article := range []string{"A", "B", "C", "D"} {
+		batched.Notify(article)
+	} // Batch: [A B C]
+	batcher.Flush() // Batch: [D]
+
+	fmt.Println("Webhook failures:", monitored.Snapshot().FailuresPerSubscriber[flaky]) // Webhook failures: 2
+
 }
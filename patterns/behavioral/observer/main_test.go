@@ -0,0 +1,415 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+func TestMetricsCountDeliveriesAndFailures(t *testing.T) {
+	p := &Publisher{}
+	ok, bad := &safeRecorder{}, &safeRecorder{err: errors.New("nack")}
+	p.Register(ok)
+	p.Register(bad)
+	for _, article := range []string{"a", "b", "c"} {
+		p.Notify(article)
+	}
+	p.Unregister(ok)
+	p.NotifyPool("d", 2)
+
+	m := p.Snapshot()
+	tests := []struct {
+		name                 string
+		sub                  Subscriber
+		deliveries, failures int
+	}{
+		{"ok", ok, 3, 0},
+		{"bad", bad, 0, 4},
+	}
+	for _, tt := range tests {
+		if got := m.DeliveriesPerSubscriber[tt.sub]; got != tt.deliveries {
+			t.Errorf("deliveries[%s] = %d, want %d", tt.name, got, tt.deliveries)
+		}
+		if got := m.FailuresPerSubscriber[tt.sub]; got != tt.failures {
+			t.Errorf("failures[%s] = %d, want %d", tt.name, got, tt.failures)
+		}
+	}
+	if m.TotalNotifications != 4 {
+		t.Errorf("TotalNotifications = %d, want 4", m.TotalNotifications)
+	}
+
+	m.DeliveriesPerSubscriber[ok] = 100
+	if p.Snapshot().DeliveriesPerSubscriber[ok] != 3 {
+		t.Error("Snapshot shares its maps with the publisher")
+	}
+}
+
+func TestNotifyRateLimitedPacesOnClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := &Publisher{}
+	p.SetClock(fake)
+	recs := []*safeRecorder{{}, {}, {}}
+	for _, r := range recs {
+		p.Register(r)
+	}
+	delivered := func() []int {
+		n := make([]int, len(recs))
+		for i, r := range recs {
+			n[i] = len(r.articles())
+		}
+		return n
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.NotifyRateLimited("paced", 4) // one delivery every 250ms
+		close(done)
+	}()
+
+	for _, want := range [][]int{{1, 0, 0}, {1, 1, 0}} {
+		fake.BlockUntil(1) // waiting before the next delivery
+		if got := delivered(); !slices.Equal(got, want) {
+			t.Fatalf("delivered %v before advancing, want %v", got, want)
+		}
+		fake.Advance(250 * time.Millisecond)
+	}
+	<-done
+	if got := delivered(); !slices.Equal(got, []int{1, 1, 1}) {
+		t.Errorf("delivered %v, want [1 1 1]", got)
+	}
+}
+
+func TestNotifyRateLimitedRates(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond int
+	}{
+		{"falls back to Notify", 0},
+		{"negative falls back to Notify", -1},
+		{"one per nanosecond", 1e9},
+		{"faster than a nanosecond", 2e9},
+		{"max int", math.MaxInt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Publisher{}
+			recs := []*safeRecorder{{}, {}}
+			for _, r := range recs {
+				p.Register(r)
+			}
+			p.NotifyRateLimited("fast", tt.perSecond)
+			for i, r := range recs {
+				if got := r.articles(); !slices.Equal(got, []string{"fast"}) {
+					t.Errorf("subscriber %d got %v", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchingSubscriberDeliversInOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		active  int
+		overlap bool
+		got     []string
+	)
+	b := NewBatchingSubscriber(3, time.Microsecond, func(batch []string) {
+		mu.Lock()
+		active++
+		overlap = overlap || active > 1
+		got = append(got, batch...)
+		mu.Unlock()
+		time.Sleep(10 * time.Microsecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+
+	var want []string
+	for i := 0; i < 200; i++ {
+		article := fmt.Sprint(i)
+		want = append(want, article)
+		b.Update(article)
+	}
+	b.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if overlap {
+		t.Error("deliver ran concurrently")
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestBatchingSubscriberFlush(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		articles []string
+		want     [][]string
+	}{
+		{"full batch", 3, []string{"A", "B", "C"}, [][]string{{"A", "B", "C"}}},
+		{"full then remainder", 2, []string{"A", "B", "C"}, [][]string{{"A", "B"}, {"C"}}},
+		{"partial", 5, []string{"A"}, [][]string{{"A"}}},
+		{"empty", 3, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			b := NewBatchingSubscriber(tt.size, 0, func(batch []string) {
+				got = append(got, batch)
+			})
+			for _, a := range tt.articles {
+				b.Update(a)
+			}
+			b.Flush()
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// safeRecorder is a Subscriber that records articles and can be told to fail.
+type safeRecorder struct {
+	err  error         // returned from every Update
+	wait chan struct{} // if set, Update blocks until it is closed
+
+	mu  sync.Mutex
+	got []string
+}
+
+func (r *safeRecorder) Update(article string) error {
+	if r.wait != nil {
+		<-r.wait
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, article)
+	return r.err
+}
+
+func (r *safeRecorder) articles() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.got)
+}
+
+func TestRegisterUnsubscribe(t *testing.T) {
+	p := &Publisher{}
+	a, b := &safeRecorder{}, &safeRecorder{}
+	unsubA := p.Register(a)
+	p.Register(b)
+	p.Register(a) // a second registration of the same subscriber
+
+	p.Notify("1")
+	unsubA()
+	unsubA() // no further effect
+	p.Notify("2")
+	p.Unregister(a)
+	p.Unregister(&safeRecorder{}) // not registered
+	p.Notify("3")
+
+	if got := a.articles(); !slices.Equal(got, []string{"1", "1", "2"}) {
+		t.Errorf("a got %v", got)
+	}
+	if got := b.articles(); !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("b got %v", got)
+	}
+}
+
+// hookSubscriber runs hook on every Update.
+type hookSubscriber struct {
+	hook func()
+}
+
+func (s *hookSubscriber) Update(string) error {
+	s.hook()
+	return nil
+}
+
+func TestUnsubscribeDuringNotify(t *testing.T) {
+	p := &Publisher{}
+	late := &safeRecorder{}
+	var unsubLate func()
+	p.Register(&hookSubscriber{hook: func() { unsubLate() }})
+	unsubLate = p.Register(late)
+
+	p.Notify("1") // already iterating the old slice: late still gets it
+	p.Notify("2")
+	if got := late.articles(); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("late got %v, want [1]", got)
+	}
+}
+
+func TestSetMaxFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		max         int
+		results     []bool // per notification, true means the subscriber fails
+		wantRemoved bool
+	}{
+		{"removed after consecutive failures", 2, []bool{true, true}, true},
+		{"success resets the count", 2, []bool{true, false, true}, false},
+		{"disabled", 0, []bool{true, true, true}, false},
+		{"single failure with max 1", 1, []bool{true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Publisher{}
+			p.SetMaxFailures(tt.max)
+			rec := &safeRecorder{}
+			p.Register(rec)
+			for _, fail := range tt.results {
+				rec.err = nil
+				if fail {
+					rec.err = errors.New("down")
+				}
+				p.Notify("x")
+			}
+			p.Notify("probe")
+			removed := len(rec.articles()) == len(tt.results)
+			if removed != tt.wantRemoved {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestNotifyWithAck(t *testing.T) {
+	errDown := errors.New("down")
+	p := &Publisher{}
+	ok, failing := &safeRecorder{}, &safeRecorder{err: errDown}
+	p.Register(ok)
+	p.Register(failing)
+	acks := p.NotifyWithAck("x")
+	if len(acks) != 2 || acks[ok] != nil || !errors.Is(acks[failing], errDown) {
+		t.Errorf("acks = %v", acks)
+	}
+	if got := (&Publisher{}).NotifyWithAck("x"); len(got) != 0 {
+		t.Errorf("acks with no subscribers = %v", got)
+	}
+}
+
+func TestNotifySeq(t *testing.T) {
+	p := &Publisher{}
+	steady, flaky := &safeRecorder{}, &safeRecorder{}
+	p.Register(steady)
+	p.Register(flaky)
+
+	steps := []struct {
+		seq  int
+		fail bool
+	}{
+		{1, false},
+		{3, false},
+		{2, false}, // stale
+		{3, false}, // duplicate
+		{4, true},  // flaky fails: its seq stays at 3
+		{4, false}, // so the retry reaches it
+		{5, false},
+	}
+	for _, s := range steps {
+		flaky.err = nil
+		if s.fail {
+			flaky.err = errors.New("down")
+		}
+		p.NotifySeq(s.seq, fmt.Sprint(s.seq))
+	}
+	if got := steady.articles(); !slices.Equal(got, []string{"1", "3", "4", "5"}) {
+		t.Errorf("steady got %v, want [1 3 4 5]", got)
+	}
+	if got := flaky.articles(); !slices.Equal(got, []string{"1", "3", "4", "4", "5"}) {
+		t.Errorf("flaky got %v, want [1 3 4 4 5]", got)
+	}
+}
+
+func TestNotifyAsyncAndClose(t *testing.T) {
+	p := &Publisher{}
+	rec := &safeRecorder{wait: make(chan struct{})}
+	p.Register(rec)
+
+	for _, a := range []string{"a", "b", "c"} {
+		if err := p.NotifyAsync(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned before in-flight deliveries finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(rec.wait)
+	<-closed
+
+	if got := rec.articles(); len(got) != 3 {
+		t.Errorf("delivered %v, want all three articles", got)
+	}
+	if err := p.NotifyAsync("late"); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("NotifyAsync after Close = %v, want ErrPublisherClosed", err)
+	}
+	p.Close() // closing twice is harmless
+}
+
+func TestNotifyPool(t *testing.T) {
+	for _, workers := range []int{-1, 0, 1, 4, 50} {
+		t.Run(fmt.Sprint("workers=", workers), func(t *testing.T) {
+			p := &Publisher{}
+			recs := make([]*safeRecorder, 100)
+			for i := range recs {
+				recs[i] = &safeRecorder{}
+				p.Register(recs[i])
+			}
+			p.NotifyPool("x", workers)
+			for i, r := range recs {
+				if got := r.articles(); !slices.Equal(got, []string{"x"}) {
+					t.Errorf("subscriber %d got %v", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPublisherConcurrentUse(t *testing.T) {
+	p := &Publisher{}
+	p.SetMaxFailures(3)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			unsub := p.Register(&safeRecorder{})
+			if i%2 == 0 {
+				unsub()
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			p.NotifySeq(i, "seq")
+			p.NotifyPool("pool", 2)
+		}(i)
+		go func() {
+			defer wg.Done()
+			p.Notify("plain")
+			p.Snapshot()
+		}()
+	}
+	wg.Wait()
+	if got := len(p.snapshot()); got != 5 {
+		t.Errorf("%d subscribers left, want 5", got)
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Alerta interface {
 	Actualizar(mensaje string)
@@ -18,6 +21,7 @@ func (a *AlertaWeb) Actualizar(mensaje string) {
 
 type EstacionMeteorologica struct {
 	subscribers []Alerta
+	grupos      map[string][]Alerta
 }
 
 func (e *EstacionMeteorologica) Registrar(alert Alerta) {
@@ -31,12 +35,59 @@ func (e *EstacionMeteorologica) Eliminar(alert Alerta) {
 		}
 	}
 }
+
+// RegistrarEnGrupo suscribe la alerta solo a los mensajes del grupo.
+func (e *EstacionMeteorologica) RegistrarEnGrupo(grupo string, alert Alerta) {
+	if e.grupos == nil {
+		e.grupos = make(map[string][]Alerta)
+	}
+	e.grupos[grupo] = append(e.grupos[grupo], alert)
+}
+
+func (e *EstacionMeteorologica) NotificarGrupo(grupo, mensaje string) {
+	for _, s := range e.grupos[grupo] {
+		s.Actualizar(mensaje)
+	}
+}
+
 func (e *EstacionMeteorologica) Notificar(mensaje string) {
 	for _, s := range e.subscribers {
 		s.Actualizar(mensaje)
 	}
 }
 
+// NotificarConTimeout entrega el mensaje a cada suscriptor en su propia
+// goroutine y abandona los que tardan más de timeout. Devuelve cuántas
+// entregas se abandonaron.
+func (e *EstacionMeteorologica) NotificarConTimeout(mensaje string, timeout time.Duration) int {
+	abandonadas := 0
+	for _, s := range e.subscribers {
+		hecho := make(chan struct{})
+		go func(s Alerta) {
+			defer close(hecho)
+			s.Actualizar(mensaje)
+		}(s)
+
+		select {
+		case <-hecho:
+		case <-time.After(timeout):
+			fmt.Printf("Entrega abandonada tras %v: %T\n", timeout, s)
+			abandonadas++
+		}
+	}
+	return abandonadas
+}
+
+// AlertaLenta simula un suscriptor que tarda en procesar
+type AlertaLenta struct {
+	Demora time.Duration
+}
+
+func (a *AlertaLenta) Actualizar(mensaje string) {
+	time.Sleep(a.Demora)
+	fmt.Println("Alerta lenta recibida:", mensaje)
+}
+
 func main() {
 	estacion := &EstacionMeteorologica{}
 	movil := &AlertaMovil{}
@@ -50,4 +101,14 @@ func main() {
 
 	estacion.Eliminar(movil)
 	estacion.Notificar("Lluvia intensa")
+
+	estacion.Registrar(&AlertaLenta{Demora: time.Second})
+	abandonadas := estacion.NotificarConTimeout("Granizo", 100*time.Millisecond)
+	fmt.Println("Entregas abandonadas:", abandonadas) // 1
+
+	// Grupos por severidad
+	estacion.RegistrarEnGrupo("critico", movil)
+	estacion.RegistrarEnGrupo("info", web)
+	estacion.NotificarGrupo("critico", "Alerta de huracán") // solo la alerta móvil
+	estacion.NotificarGrupo("info", "Cielo despejado")      // solo la alerta web
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// alertaGrabadora records every message it receives, after an optional delay.
+type alertaGrabadora struct {
+	demora time.Duration
+
+	mu       sync.Mutex
+	mensajes []string
+}
+
+func (a *alertaGrabadora) Actualizar(mensaje string) {
+	time.Sleep(a.demora)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.mensajes = append(a.mensajes, mensaje)
+}
+
+func (a *alertaGrabadora) recibidos() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.mensajes)
+}
+
+func TestNotificarGrupo(t *testing.T) {
+	critico, info := &alertaGrabadora{}, &alertaGrabadora{}
+	e := &EstacionMeteorologica{}
+	e.RegistrarEnGrupo("critico", critico)
+	e.RegistrarEnGrupo("info", info)
+
+	tests := []struct {
+		grupo         string
+		critico, info []string
+	}{
+		{"critico", []string{"m"}, nil},
+		{"info", []string{"m"}, []string{"m"}},
+		{"desconocido", []string{"m"}, []string{"m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.grupo, func(t *testing.T) {
+			e.NotificarGrupo(tt.grupo, "m")
+			if got := critico.recibidos(); !slices.Equal(got, tt.critico) {
+				t.Errorf("critico received %v, want %v", got, tt.critico)
+			}
+			if got := info.recibidos(); !slices.Equal(got, tt.info) {
+				t.Errorf("info received %v, want %v", got, tt.info)
+			}
+		})
+	}
+	e.Notificar("todos")
+	if got := critico.recibidos(); len(got) != 1 {
+		t.Errorf("Notificar reached a group-only subscriber: %v", got)
+	}
+}
+
+func TestNotificarConTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		demoras         []time.Duration
+		wantAbandonadas int
+	}{
+		{"all fast", []time.Duration{0, 0}, 0},
+		{"one slow", []time.Duration{0, time.Second}, 1},
+		{"all slow", []time.Duration{time.Second, time.Second}, 2},
+		{"no subscribers", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EstacionMeteorologica{}
+			alertas := make([]*alertaGrabadora, len(tt.demoras))
+			for i, d := range tt.demoras {
+				alertas[i] = &alertaGrabadora{demora: d}
+				e.Registrar(alertas[i])
+			}
+			if got := e.NotificarConTimeout("m", 50*time.Millisecond); got != tt.wantAbandonadas {
+				t.Errorf("abandoned %d deliveries, want %d", got, tt.wantAbandonadas)
+			}
+			for i, a := range alertas {
+				if tt.demoras[i] == 0 && len(a.recibidos()) != 1 {
+					t.Errorf("fast subscriber %d did not receive the message", i)
+				}
+			}
+		})
+	}
+}
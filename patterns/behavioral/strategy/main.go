@@ -1,6 +1,11 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abrahamcorales/golang/patterns/creational/factory"
+)
 
 // Strategy
 type PaymentStrategy interface {
@@ -24,15 +29,132 @@ func (p *PayPal) Pay(amount float64) {
 	fmt.Printf("Paid $%.2f using PayPal (%s)\n", amount, p.Email)
 }
 
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// PreparablePayment is implemented by strategies that can check a payment
+// before committing to it.
+type PreparablePayment interface {
+	Prepare(amount float64) error
+}
+
+type GiftCard struct {
+	Code    string
+	Balance float64
+}
+
+func (g *GiftCard) Prepare(amount float64) error {
+	if amount > g.Balance {
+		return fmt.Errorf("gift card %s: %w", g.Code, ErrInsufficientBalance)
+	}
+	return nil
+}
+
+func (g *GiftCard) Pay(amount float64) {
+	g.Balance -= amount
+	fmt.Printf("Paid $%.2f using Gift Card (%s)\n", amount, g.Code)
+}
+
+// Composite - splits a payment evenly across several strategies
+type CompositePaymentStrategy struct {
+	Strategies []PaymentStrategy
+}
+
+func (c *CompositePaymentStrategy) Pay(amount float64) {
+	if err := c.TryPay(amount); err != nil {
+		fmt.Printf("Payment of $%.2f aborted: %v\n", amount, err)
+	}
+}
+
+// TryPay is a two-phase commit: every PreparablePayment must accept its share
+// before any strategy is charged. If one refuses, nothing is paid.
+func (c *CompositePaymentStrategy) TryPay(amount float64) error {
+	shares := c.split(amount)
+	for i, s := range c.Strategies {
+		if p, ok := s.(PreparablePayment); ok {
+			if err := p.Prepare(shares[i]); err != nil {
+				return err
+			}
+		}
+	}
+	for i, s := range c.Strategies {
+		s.Pay(shares[i])
+	}
+	return nil
+}
+
+// split divides amount evenly; the last share absorbs any rounding remainder.
+func (c *CompositePaymentStrategy) split(amount float64) []float64 {
+	n := len(c.Strategies)
+	shares := make([]float64, n)
+	if n == 0 {
+		return shares
+	}
+	each := amount / float64(n)
+	for i := range shares {
+		shares[i] = each
+	}
+	shares[n-1] = amount - each*float64(n-1)
+	return shares
+}
+
+// Bridge - lets a factory.PaymentProcessor act as a PaymentStrategy
+type ProcessorStrategyBridge struct {
+	Processor factory.PaymentProcessor
+}
+
+func (b *ProcessorStrategyBridge) Pay(amount float64) {
+	if err := b.Processor.ProcessPayment(amount); err != nil {
+		fmt.Printf("Payment of $%.2f failed: %v\n", amount, err)
+	}
+}
+
+type LineItem struct {
+	Name  string
+	Price float64
+}
+
 // Context
 type ShoppingCart struct {
 	Payment PaymentStrategy
+	Items   []LineItem
 }
 
 func (s *ShoppingCart) Checkout(amount float64) {
 	s.Payment.Pay(amount)
 }
 
+func (s *ShoppingCart) AddItem(name string, price float64) {
+	s.Items = append(s.Items, LineItem{Name: name, Price: price})
+}
+
+func (s *ShoppingCart) Total() float64 {
+	total := 0.0
+	for _, item := range s.Items {
+		total += item.Price
+	}
+	return total
+}
+
+// Memento - snapshot of a cart's strategy and items
+type CartMemento struct {
+	payment PaymentStrategy
+	items   []LineItem
+}
+
+// Save returns a memento holding its own copy of the items, so later cart
+// edits do not change it.
+func (s *ShoppingCart) Save() CartMemento {
+	return CartMemento{
+		payment: s.Payment,
+		items:   append([]LineItem(nil), s.Items...),
+	}
+}
+
+func (s *ShoppingCart) Restore(m CartMemento) {
+	s.Payment = m.payment
+	s.Items = append([]LineItem(nil), m.items...)
+}
+
 func main() {
 	cart := &ShoppingCart{}
 
@@ -42,6 +164,32 @@ func main() {
 	cart.Payment = &PayPal{Email: "alice@example.com"}
 	cart.Checkout(25.0) // Paid $25.00 using PayPal (alice@example.com)
 
+	cart.Payment = &ProcessorStrategyBridge{Processor: factory.PayPalProcessor{}}
+	cart.Checkout(10.0) // [PayPal] Payment of $10.00 processed successfully.
+
+	// Memento: save for later and restore
+	cart.AddItem("Book", 15)
+	saved := cart.Save()
+	cart.AddItem("Laptop", 900)
+	cart.Payment = &CreditCard{Name: "Alice", CardNumber: "1234-5678"}
+	cart.Restore(saved)
+	cart.Checkout(cart.Total()) // [PayPal] Payment of $15.00 processed successfully.
+
+	// Composite: split across methods, all or nothing
+	gift := &GiftCard{Code: "GIFT-1", Balance: 30}
+	cart.Payment = &CompositePaymentStrategy{Strategies: []PaymentStrategy{gift, &PayPal{Email: "alice@example.com"}}}
+	cart.Checkout(40) // Paid $20.00 using Gift Card (GIFT-1), Paid $20.00 using PayPal (alice@example.com)
+	cart.Checkout(40) // Payment of $40.00 aborted: gift card GIFT-1: insufficient balance
+
+	// Mediator: shipping is free above $100
+	checkout := &CheckoutMediator{
+		Payment:          &PayPal{Email: "alice@example.com"},
+		Shipping:         &ExpressShipping{},
+		FreeShippingOver: 100,
+	}
+	checkout.Checkout(40, 50)  // Paid $45.00 using PayPal (alice@example.com)
+	checkout.Checkout(120, 50) // Paid $120.00 using PayPal (alice@example.com)
+
 	// Exercise implementation
 	fmt.Println("\n=== SHIPPING STRATEGY EXERCISE ===")
 
@@ -101,6 +249,7 @@ Overnight: Overnight delivery
 
 type ShippingStrategy interface {
 	Ship(item string, distance int)
+	Cost(distance int) float64
 }
 
 type ShippingContext struct {
@@ -117,18 +266,50 @@ func (s *StandardShipping) Ship(item string, distance int) {
 	fmt.Printf("Standard: Standard delivery in 5-7 days\n")
 }
 
+func (s *StandardShipping) Cost(distance int) float64 {
+	return 0.05 * float64(distance)
+}
+
 type ExpressShipping struct{}
 
 func (e *ExpressShipping) Ship(item string, distance int) {
 	fmt.Printf("Express: Express delivery in 2-3 days\n")
 }
 
+func (e *ExpressShipping) Cost(distance int) float64 {
+	return 0.10 * float64(distance)
+}
+
 type OvernightShipping struct{}
 
 func (o *OvernightShipping) Ship(item string, distance int) {
 	fmt.Printf("Overnight: Overnight delivery\n")
 }
 
+func (o *OvernightShipping) Cost(distance int) float64 {
+	return 0.25 * float64(distance)
+}
+
+// Mediator - coordinates payment and shipping so neither strategy needs to
+// know about the other
+type CheckoutMediator struct {
+	Payment          PaymentStrategy
+	Shipping         ShippingStrategy
+	FreeShippingOver float64
+}
+
+// Checkout charges amount plus shipping for distance, waiving shipping when
+// amount exceeds FreeShippingOver. It returns the total charged.
+func (m *CheckoutMediator) Checkout(amount float64, distance int) float64 {
+	shipping := m.Shipping.Cost(distance)
+	if amount > m.FreeShippingOver {
+		shipping = 0
+	}
+	total := amount + shipping
+	m.Payment.Pay(total)
+	return total
+}
+
 /*
 EXERCISE 2: Simple Greeting Strategy
 
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abrahamcorales/golang/patterns/creational/factory"
+)
+
+// recordingStrategy remembers every amount it was charged.
+type recordingStrategy struct {
+	paid []float64
+}
+
+func (r *recordingStrategy) Pay(amount float64) { r.paid = append(r.paid, amount) }
+
+// failingProcessor is a factory.PaymentProcessor that always fails.
+type failingProcessor struct {
+	calls int
+}
+
+func (f *failingProcessor) ProcessPayment(float64) error {
+	f.calls++
+	return errors.New("declined")
+}
+
+// spyProcessor records the amounts and results of the processor it wraps.
+type spyProcessor struct {
+	factory.PaymentProcessor
+	amounts []float64
+	errs    []error
+}
+
+func (s *spyProcessor) ProcessPayment(amount float64) error {
+	err := s.PaymentProcessor.ProcessPayment(amount)
+	s.amounts = append(s.amounts, amount)
+	s.errs = append(s.errs, err)
+	return err
+}
+
+func TestCompositeTryPay(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     float64
+		amount      float64
+		wantErr     error
+		wantPaid    []float64 // by the recording strategy
+		wantBalance float64
+	}{
+		{"both charged", 30, 40, nil, []float64{20}, 10},
+		{"exact balance", 20, 40, nil, []float64{20}, 0},
+		{"gift card refuses", 10, 40, ErrInsufficientBalance, nil, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gift := &GiftCard{Code: "G", Balance: tt.balance}
+			rec := &recordingStrategy{}
+			c := &CompositePaymentStrategy{Strategies: []PaymentStrategy{gift, rec}}
+			if err := c.TryPay(tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryPay() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(rec.paid, tt.wantPaid) {
+				t.Errorf("other strategy paid %v, want %v", rec.paid, tt.wantPaid)
+			}
+			if gift.Balance != tt.wantBalance {
+				t.Errorf("gift balance = %v, want %v", gift.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestCompositeSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		amount float64
+		want   []float64
+	}{
+		{"even", 2, 10, []float64{5, 5}},
+		{"quarters", 4, 10, []float64{2.5, 2.5, 2.5, 2.5}},
+		{"single", 1, 7, []float64{7}},
+		{"none", 0, 7, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CompositePaymentStrategy{Strategies: make([]PaymentStrategy, tt.n)}
+			got := c.split(tt.amount)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("split(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+
+	// The last share absorbs the rounding remainder, so shares add up exactly.
+	shares := (&CompositePaymentStrategy{Strategies: make([]PaymentStrategy, 3)}).split(10)
+	if sum := shares[0] + shares[1] + shares[2]; sum != 10 {
+		t.Errorf("shares %v add up to %v, want 10", shares, sum)
+	}
+}
+
+func TestCartMemento(t *testing.T) {
+	first, second := &recordingStrategy{}, &recordingStrategy{}
+	cart := &ShoppingCart{Payment: first}
+	cart.AddItem("Book", 15)
+	saved := cart.Save()
+
+	cart.AddItem("Laptop", 900)
+	cart.Items[0].Price = 1 // must not leak into the memento
+	cart.Payment = second
+	if got := cart.Total(); got != 901 {
+		t.Errorf("Total() before restore = %v, want 901", got)
+	}
+
+	cart.Restore(saved)
+	cart.Checkout(cart.Total())
+	if !slices.Equal(first.paid, []float64{15}) || len(second.paid) != 0 {
+		t.Errorf("after restore first paid %v, second paid %v", first.paid, second.paid)
+	}
+
+	cart.AddItem("Pen", 2) // must not change the memento either
+	cart.Restore(saved)
+	if got := cart.Total(); got != 15 {
+		t.Errorf("Total() after second restore = %v, want 15", got)
+	}
+}
+
+func TestCheckoutMediator(t *testing.T) {
+	tests := []struct {
+		name     string
+		shipping ShippingStrategy
+		amount   float64
+		distance int
+		want     float64
+	}{
+		{"standard", &StandardShipping{}, 40, 100, 45},
+		{"express", &ExpressShipping{}, 40, 50, 45},
+		{"overnight", &OvernightShipping{}, 40, 100, 65},
+		{"free above threshold", &OvernightShipping{}, 120, 100, 120},
+		{"at threshold still pays", &StandardShipping{}, 100, 100, 105},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingStrategy{}
+			m := &CheckoutMediator{Payment: rec, Shipping: tt.shipping, FreeShippingOver: 100}
+			if got := m.Checkout(tt.amount, tt.distance); got != tt.want {
+				t.Errorf("Checkout() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(rec.paid, []float64{tt.want}) {
+				t.Errorf("charged %v, want [%v]", rec.paid, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessorStrategyBridge(t *testing.T) {
+	p := &failingProcessor{}
+	(&ProcessorStrategyBridge{Processor: p}).Pay(10) // must not panic on failure
+	if p.calls != 1 {
+		t.Errorf("processor called %d times, want 1", p.calls)
+	}
+}
+
+func TestShoppingCartCheckoutWithPayPalProcessor(t *testing.T) {
+	paypal, err := factory.NewPaymentProcessor("paypal")
+	if err != nil {
+		t.Fatalf("NewPaymentProcessor(paypal): %v", err)
+	}
+	spy := &spyProcessor{PaymentProcessor: paypal}
+	cart := &ShoppingCart{Payment: &ProcessorStrategyBridge{Processor: spy}}
+	cart.AddItem("Book", 15)
+	cart.AddItem("Pen", 2.5)
+
+	cart.Checkout(cart.Total())
+	if !slices.Equal(spy.amounts, []float64{17.5}) {
+		t.Errorf("PayPal charged %v, want [17.5]", spy.amounts)
+	}
+	if err := errors.Join(spy.errs...); err != nil {
+		t.Errorf("PayPal checkout failed: %v", err)
+	}
+}
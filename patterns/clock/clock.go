@@ -0,0 +1,95 @@
+// Package clock is the time source shared by the pattern examples. Types
+// that read the time hold a Clock, default to System, and accept a Fake
+// through SetClock so tests can drive time deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so scheduling can be driven deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the real wall clock.
+type System struct{}
+
+func (System) Now() time.Time                         { return time.Now() }
+func (System) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Or returns c, or System if c is nil.
+func Or(c Clock) Clock {
+	if c == nil {
+		return System{}
+	}
+	return c
+}
+
+// Fake only moves when Advance is called. Channels returned by After fire
+// once the fake time reaches their deadline.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond // signalled when a waiter is added
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewFake(start time.Time) *Fake {
+	c := &Fake{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Fake) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.changed.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every waiter that is due.
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// BlockUntil waits until at least n After calls are waiting to fire. Call it
+// before Advance when another goroutine is about to wait on the clock, so
+// the wait is registered before time moves.
+func (c *Fake) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
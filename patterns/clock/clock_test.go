@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	c := NewFake(start)
+	soon, later := c.After(time.Second), c.After(time.Minute)
+	if !fired(c.After(0)) {
+		t.Error("After(0) did not fire immediately")
+	}
+
+	tests := []struct {
+		advance             time.Duration
+		wantSoon, wantLater bool
+	}{
+		{500 * time.Millisecond, false, false},
+		{500 * time.Millisecond, true, false},
+		{time.Hour, false, true},
+	}
+	for i, tt := range tests {
+		c.Advance(tt.advance)
+		if got := fired(soon); got != tt.wantSoon {
+			t.Errorf("step %d: soon fired = %v, want %v", i, got, tt.wantSoon)
+		}
+		if got := fired(later); got != tt.wantLater {
+			t.Errorf("step %d: later fired = %v, want %v", i, got, tt.wantLater)
+		}
+	}
+	if want := start.Add(time.Hour + time.Second); !c.Now().Equal(want) {
+		t.Errorf("Now() = %v, want %v", c.Now(), want)
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	c := NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		<-c.After(time.Hour)
+		close(done)
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiter registered before Advance did not fire")
+	}
+}
+
+func TestOr(t *testing.T) {
+	if _, ok := Or(nil).(System); !ok {
+		t.Error("Or(nil) is not System")
+	}
+	f := NewFake(start)
+	if Or(f) != Clock(f) {
+		t.Error("Or(f) did not return f")
+	}
+}
@@ -1,21 +1,68 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
+const vinLength = 17
+
+var ErrInvalidVIN = errors.New("VIN must be exactly 17 characters")
+
 type Car struct {
-	Brand    string
-	Model    string
-	Year     int
-	Color    string
-	Electric bool
+	Brand      string `json:"brand"`
+	Model      string `json:"model"`
+	Year       int    `json:"year"`
+	Color      string `json:"color"`
+	Electric   bool   `json:"electric"`
+	Engine     string `json:"engine"`
+	VIN        string `json:"vin"`
+	Horsepower int    `json:"horsepower"`
 }
 
 func NewCarBuilder() *Car {
 	return &Car{}
 }
 
+// NewCarBuilderFrom seeds a builder with a copy of an existing car.
+func NewCarBuilderFrom(c Car) *Car {
+	clone := c.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of the car. All fields are values today; any
+// future slice or map fields must be copied here too.
+func (c Car) Clone() Car {
+	return c
+}
+
+// String renders the car as "2024 Red Ford Mustang (gas)", skipping unset fields.
+func (c Car) String() string {
+	var parts []string
+	if c.Year != 0 {
+		parts = append(parts, strconv.Itoa(c.Year))
+	}
+	for _, p := range []string{c.Color, c.Brand, c.Model} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	power := "gas"
+	if c.Electric {
+		power = "electric"
+	}
+	parts = append(parts, "("+power+")")
+	return strings.Join(parts, " ")
+}
+
+// Equals reports whether both cars have identical fields.
+func (c Car) Equals(other Car) bool {
+	return c == other
+}
+
 func (c *Car) WithBrand(name string) *Car {
 	c.Brand = name
 	return c
@@ -41,10 +88,163 @@ func (c *Car) WithElectric(electric bool) *Car {
 	return c
 }
 
+func (c *Car) WithEngine(engine string) *Car {
+	c.Engine = engine
+	return c
+}
+
+func (c *Car) WithVIN(vin string) *Car {
+	c.VIN = vin
+	return c
+}
+
+func (c *Car) WithHorsepower(hp int) *Car {
+	c.Horsepower = hp
+	return c
+}
+
 func (c *Car) Build() Car {
 	return *c
 }
 
+// BuildValidated is like Build but rejects cars with an invalid VIN.
+func (c *Car) BuildValidated() (Car, error) {
+	if len(c.VIN) != vinLength {
+		return Car{}, fmt.Errorf("%w: got %d", ErrInvalidVIN, len(c.VIN))
+	}
+	return *c, nil
+}
+
+// Reset zeroes every field so the same builder can be reused for another car.
+func (c *Car) Reset() *Car {
+	*c = Car{}
+	return c
+}
+
+func (c Car) ToJSON() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// CarFromJSON returns a builder preloaded from data so callers can keep chaining.
+func CarFromJSON(data []byte) (*Car, error) {
+	c := NewCarBuilder()
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("decode car: %w", err)
+	}
+	return c, nil
+}
+
+// CarBrandStep is the first stage of the staged builder. It only exposes
+// WithBrand, so a car cannot be built without a brand:
+//
+//	car := NewCarBuilder2().WithBrand("Ford").WithModel("Focus").Build()
+//	NewCarBuilder2().Build() // does not compile
+type CarBrandStep struct{}
+
+func NewCarBuilder2() CarBrandStep {
+	return CarBrandStep{}
+}
+
+// WithBrand moves to the second stage with the brand already set.
+func (CarBrandStep) WithBrand(name string) CarDetailsStep {
+	return CarDetailsStep{car: Car{Brand: name}}
+}
+
+// CarDetailsStep is the second stage of the staged builder. It sets the
+// optional fields and builds the car; the brand can no longer be changed.
+// Every method returns a new step, so a step can be reused as a template.
+type CarDetailsStep struct {
+	car Car
+}
+
+func (s CarDetailsStep) WithModel(model string) CarDetailsStep {
+	s.car.WithModel(model)
+	return s
+}
+
+func (s CarDetailsStep) WithYear(year int) CarDetailsStep {
+	s.car.WithYear(year)
+	return s
+}
+
+func (s CarDetailsStep) WithColor(color string) CarDetailsStep {
+	s.car.WithColor(color)
+	return s
+}
+
+func (s CarDetailsStep) WithElectric(electric bool) CarDetailsStep {
+	s.car.WithElectric(electric)
+	return s
+}
+
+func (s CarDetailsStep) WithEngine(engine string) CarDetailsStep {
+	s.car.WithEngine(engine)
+	return s
+}
+
+func (s CarDetailsStep) WithVIN(vin string) CarDetailsStep {
+	s.car.WithVIN(vin)
+	return s
+}
+
+func (s CarDetailsStep) WithHorsepower(hp int) CarDetailsStep {
+	s.car.WithHorsepower(hp)
+	return s
+}
+
+func (s CarDetailsStep) Build() Car {
+	return s.car
+}
+
+// BuildValidated is like Build but rejects cars with an invalid VIN.
+func (s CarDetailsStep) BuildValidated() (Car, error) {
+	return s.car.BuildValidated()
+}
+
+// CarOption configures a car built with NewCar.
+type CarOption func(*Car)
+
+// NewCar builds a car from options; unspecified fields keep their zero value.
+func NewCar(opts ...CarOption) Car {
+	var c Car
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func WithBrandOpt(brand string) CarOption   { return func(c *Car) { c.Brand = brand } }
+func WithModelOpt(model string) CarOption   { return func(c *Car) { c.Model = model } }
+func WithYearOpt(year int) CarOption        { return func(c *Car) { c.Year = year } }
+func WithColorOpt(color string) CarOption   { return func(c *Car) { c.Color = color } }
+func WithElectricOpt(e bool) CarOption      { return func(c *Car) { c.Electric = e } }
+func WithEngineOpt(engine string) CarOption { return func(c *Car) { c.Engine = engine } }
+func WithVINOpt(vin string) CarOption       { return func(c *Car) { c.VIN = vin } }
+func WithHorsepowerOpt(hp int) CarOption    { return func(c *Car) { c.Horsepower = hp } }
+
+// CarDirector encodes reusable build recipes on top of the builder.
+type CarDirector struct{}
+
+func (d CarDirector) BuildElectricSUV(brand string) Car {
+	return NewCarBuilder().
+		WithBrand(brand).
+		WithModel("SUV").
+		WithYear(2024).
+		WithColor("White").
+		WithElectric(true).
+		Build()
+}
+
+func (d CarDirector) BuildSportsCar(brand string) Car {
+	return NewCarBuilder().
+		WithBrand(brand).
+		WithModel("Sports").
+		WithYear(2024).
+		WithColor("Red").
+		WithElectric(false).
+		Build()
+}
+
 func main() {
 
 	car := NewCarBuilder().
@@ -54,6 +254,55 @@ func main() {
 		WithColor("Red").
 		WithElectric(false).
 		Build()
-	fmt.Println(car)
+	fmt.Println(car) // 2024 Red Ford Mustang (gas)
+
+	// Reuse one builder for several cars; Build returns a copy so
+	// earlier cars are not affected by Reset.
+	builder := NewCarBuilder()
+	first := builder.WithBrand("Tesla").WithModel("Model 3").Build()
+	second := builder.Reset().WithBrand("Toyota").WithYear(2023).Build()
+	fmt.Println(first)  // Tesla Model 3 (gas)
+	fmt.Println(second) // 2023 Toyota (gas)
+
+	// Director recipes
+	director := CarDirector{}
+	fmt.Println(director.BuildElectricSUV("Rivian")) // 2024 White Rivian SUV (electric)
+	fmt.Println(director.BuildSportsCar("Porsche"))  // 2024 Red Porsche Sports (gas)
+
+	// JSON round trip
+	data, _ := car.ToJSON()
+	fmt.Println(string(data)) // {"brand":"Ford","model":"Mustang","year":2024,"color":"Red","electric":false,"engine":"","vin":"","horsepower":0}
+	restored, _ := CarFromJSON(data)
+	fmt.Println(restored.WithColor("Blue").Build()) // 2024 Blue Ford Mustang (gas)
+	if _, err := CarFromJSON([]byte("{not json")); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Engine / VIN section
+	mustang, err := NewCarBuilder().
+		WithBrand("Ford").
+		WithModel("Mustang").
+		WithEngine("5.0L V8").
+		WithHorsepower(480).
+		WithVIN("1FA6P8CF5L5100001").
+		BuildValidated()
+	fmt.Println(mustang.Engine, mustang.Horsepower, err) // 5.0L V8 480 <nil>
+	if _, err := NewCarBuilder().WithVIN("SHORT").BuildValidated(); err != nil {
+		fmt.Println("Error:", err) // Error: VIN must be exactly 17 characters: got 5
+	}
+
+	// Prototype: copy a car and tweak one field
+	blue := NewCarBuilderFrom(car).WithColor("Blue").Build()
+	fmt.Println(car.Color, blue.Color) // Red Blue
+
+	// Equality compares every field
+	fmt.Println(car.Equals(car.Clone()), car.Equals(blue)) // true false
+
+	// Functional options
+	leaf := NewCar(WithBrandOpt("Nissan"), WithModelOpt("Leaf"), WithElectricOpt(true))
+	fmt.Println(leaf) // Nissan Leaf (electric)
 
+	// Staged builder: the brand must come first
+	focus := NewCarBuilder2().WithBrand("Ford").WithModel("Focus").WithYear(2022).Build()
+	fmt.Println(focus) // 2022 Ford Focus (gas)
 }
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCarString(t *testing.T) {
+	tests := []struct {
+		name string
+		car  Car
+		want string
+	}{
+		{"full", Car{Brand: "Ford", Model: "Mustang", Year: 2024, Color: "Red"}, "2024 Red Ford Mustang (gas)"},
+		{"electric", Car{Brand: "Nissan", Model: "Leaf", Electric: true}, "Nissan Leaf (electric)"},
+		{"no year or color", Car{Brand: "Tesla", Model: "Model 3"}, "Tesla Model 3 (gas)"},
+		{"empty", Car{}, "(gas)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.car.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarEquals(t *testing.T) {
+	a := Car{Brand: "Ford", Model: "Mustang", Year: 2024, Color: "Red"}
+	tests := []struct {
+		name string
+		b    Car
+		want bool
+	}{
+		{"identical", a, true},
+		{"different year", Car{Brand: "Ford", Model: "Mustang", Year: 2023, Color: "Red"}, false},
+		{"different electric", Car{Brand: "Ford", Model: "Mustang", Year: 2024, Color: "Red", Electric: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Equals(tt.b); got != tt.want {
+				t.Errorf("Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderReset(t *testing.T) {
+	b := NewCarBuilder()
+	first := b.WithBrand("Tesla").WithModel("Model 3").WithVIN("X").Build()
+	second := b.Reset().WithBrand("Toyota").Build()
+	if first.Brand != "Tesla" || first.Model != "Model 3" {
+		t.Errorf("Reset changed an already built car: %+v", first)
+	}
+	if want := (Car{Brand: "Toyota"}); second != want {
+		t.Errorf("after Reset got %+v, want %+v", second, want)
+	}
+}
+
+func TestCarDirector(t *testing.T) {
+	tests := []struct {
+		name string
+		car  Car
+		want Car
+	}{
+		{"electric SUV", CarDirector{}.BuildElectricSUV("Rivian"),
+			Car{Brand: "Rivian", Model: "SUV", Year: 2024, Color: "White", Electric: true}},
+		{"sports car", CarDirector{}.BuildSportsCar("Porsche"),
+			Car{Brand: "Porsche", Model: "Sports", Year: 2024, Color: "Red"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.car.Equals(tt.want) {
+				t.Errorf("got %+v, want %+v", tt.car, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarJSON(t *testing.T) {
+	car := Car{Brand: "Ford", Model: "Mustang", Year: 2024, Engine: "V8", VIN: "1FA6P8CF5L5100001", Horsepower: 480}
+	data, err := car.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := CarFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Build(); got != car {
+		t.Errorf("round trip = %+v, want %+v", got, car)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty object", `{}`, false},
+		{"unknown field ignored", `{"brand":"Kia","wheels":4}`, false},
+		{"malformed", `{not json`, true},
+		{"wrong type", `{"year":"2024"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := CarFromJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if c != nil {
+					t.Error("builder returned alongside an error")
+				}
+				if !strings.HasPrefix(err.Error(), "decode car:") {
+					t.Errorf("err = %q, want decode car prefix", err)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildValidated(t *testing.T) {
+	tests := []struct {
+		name    string
+		vin     string
+		wantErr bool
+	}{
+		{"valid", "1FA6P8CF5L5100001", false},
+		{"too short", "SHORT", true},
+		{"too long", "1FA6P8CF5L51000012", true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			car, err := NewCarBuilder().WithBrand("Ford").WithVIN(tt.vin).BuildValidated()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidVIN) {
+					t.Errorf("err = %v, want %v", err, ErrInvalidVIN)
+				}
+				if car != (Car{}) {
+					t.Errorf("got %+v alongside an error", car)
+				}
+				return
+			}
+			if err != nil || car.VIN != tt.vin {
+				t.Errorf("got %+v, %v", car, err)
+			}
+		})
+	}
+}
+
+func TestNewCarBuilderFromDoesNotAlias(t *testing.T) {
+	orig := Car{Brand: "Ford", Color: "Red"}
+	blue := NewCarBuilderFrom(orig).WithColor("Blue").Build()
+	if orig.Color != "Red" || blue.Color != "Blue" {
+		t.Errorf("orig %q, copy %q", orig.Color, blue.Color)
+	}
+	if orig.Equals(blue) || !orig.Equals(orig.Clone()) {
+		t.Error("Equals disagrees with field values")
+	}
+}
+
+func TestNewCarOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []CarOption
+		want Car
+	}{
+		{"none", nil, Car{}},
+		{"all", []CarOption{
+			WithBrandOpt("Nissan"), WithModelOpt("Leaf"), WithYearOpt(2023), WithColorOpt("Blue"),
+			WithElectricOpt(true), WithEngineOpt("EM57"), WithVINOpt("V"), WithHorsepowerOpt(147),
+		}, Car{Brand: "Nissan", Model: "Leaf", Year: 2023, Color: "Blue", Electric: true, Engine: "EM57", VIN: "V", Horsepower: 147}},
+		{"later option wins", []CarOption{WithColorOpt("Red"), WithColorOpt("Green")}, Car{Color: "Green"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewCar(tt.opts...); got != tt.want {
+				t.Errorf("NewCar() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStagedBuilder(t *testing.T) {
+	got := NewCarBuilder2().WithBrand("Ford").WithModel("Focus").Build()
+	if want := (Car{Brand: "Ford", Model: "Focus"}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Each step is a value, so a partly built step works as a template.
+	base := NewCarBuilder2().WithBrand("Tesla").WithYear(2024)
+	red, blue := base.WithColor("Red").Build(), base.WithColor("Blue").Build()
+	if red.Color != "Red" || blue.Color != "Blue" || base.Build().Color != "" {
+		t.Errorf("steps share state: red %+v, blue %+v, base %+v", red, blue, base.Build())
+	}
+
+	if _, err := NewCarBuilder2().WithBrand("Ford").WithVIN("SHORT").BuildValidated(); !errors.Is(err, ErrInvalidVIN) {
+		t.Errorf("BuildValidated() error = %v, want ErrInvalidVIN", err)
+	}
+}
+
+func ExampleCarBrandStep() {
+	// NewCarBuilder2 only offers WithBrand; Build is available after it.
+	car := NewCarBuilder2().
+		WithBrand("Ford").
+		WithModel("Focus").
+		WithYear(2022).
+		Build()
+	fmt.Println(car)
+	// Output: 2022 Ford Focus (gas)
+}
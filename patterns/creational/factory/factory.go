@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 )
@@ -11,32 +13,190 @@ func main() {
 	procesor, _ := NewPaymentProcessor(provider)
 
 	_ = procesor.ProcessPayment(63)
+
+	_, policy, err := NewPaymentFamily(provider)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Refund allowed after 150 days:", policy.CanRefund(150))
+
+	withFee, err := NewPaymentProcessorWithConfig("stripe", ProcessorConfig{FeePercent: 2.9})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	_ = withFee.ProcessPayment(100) // [Stripe] Payment of $100.00 + $2.90 fee = $102.90 processed successfully.
+
+	_, err = NewPaymentProcessorWithConfig("paypal", ProcessorConfig{FeePercent: -1})
+	fmt.Println("Error:", err) // Error: fee percent must be non-negative: -1
+
+	err = Processors.RegisterWithValidation("paypal-promo",
+		func() PaymentProcessor { return PayPalProcessor{FeePercent: -5} },
+		func(p PaymentProcessor) error {
+			if pp, ok := p.(PayPalProcessor); ok && pp.FeePercent < 0 {
+				return fmt.Errorf("%w: %v", ErrNegativeFee, pp.FeePercent)
+			}
+			return nil
+		})
+	fmt.Println("Error:", err) // Error: register paypal-promo: fee percent must be non-negative: -5
+	_, err = NewPaymentProcessor("paypal-promo")
+	fmt.Println("Error:", err) // Error: unsupported payment provider: paypal-promo (not registered)
 }
 
 type PaymentProcessor interface {
 	ProcessPayment(amount float64) error
 }
-type PayPalProcessor struct{}
+
+// ContextPaymentProcessor is a PaymentProcessor that honours cancellation
+// and deadlines.
+type ContextPaymentProcessor interface {
+	PaymentProcessor
+	ProcessPaymentCtx(ctx context.Context, amount float64) error
+}
+
+var (
+	_ ContextPaymentProcessor = PayPalProcessor{}
+	_ ContextPaymentProcessor = StripeProcessor{}
+	_ ConfigurableProcessor   = PayPalProcessor{}
+	_ ConfigurableProcessor   = StripeProcessor{}
+	_ RefundPolicy            = PayPalRefundPolicy{}
+	_ RefundPolicy            = StripeRefundPolicy{}
+)
+
+var ErrNegativeFee = errors.New("fee percent must be non-negative")
+
+// ProcessorConfig holds per-provider settings applied by
+// NewPaymentProcessorWithConfig.
+type ProcessorConfig struct {
+	FeePercent float64
+}
+
+type PayPalProcessor struct {
+	FeePercent float64
+}
 
 func (p PayPalProcessor) ProcessPayment(amount float64) error {
-	fmt.Printf("[PayPal] Payment of $%.2f processed successfully.\n", amount)
+	return p.ProcessPaymentCtx(context.Background(), amount)
+}
+
+func (p PayPalProcessor) ProcessPaymentCtx(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	printCharge("PayPal", amount, p.FeePercent)
 	return nil
 }
 
-type StripeProcessor struct{}
+type StripeProcessor struct {
+	FeePercent float64
+}
 
 func (s StripeProcessor) ProcessPayment(amount float64) error {
-	fmt.Printf("[Stripe] Payment of $%.2f processed successfully.\n", amount)
+	return s.ProcessPaymentCtx(context.Background(), amount)
+}
+
+func (s StripeProcessor) ProcessPaymentCtx(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	printCharge("Stripe", amount, s.FeePercent)
 	return nil
 }
 
+// printCharge reports a processed payment, including the fee when one is
+// configured.
+func printCharge(provider string, amount, feePercent float64) {
+	if feePercent == 0 {
+		fmt.Printf("[%s] Payment of $%.2f processed successfully.\n", provider, amount)
+		return
+	}
+	fee := amount * feePercent / 100
+	fmt.Printf("[%s] Payment of $%.2f + $%.2f fee = $%.2f processed successfully.\n", provider, amount, fee, amount+fee)
+}
+
 func NewPaymentProcessor(provider string) (PaymentProcessor, error) {
-	switch provider {
-	case "paypal":
-		return PayPalProcessor{}, nil
-	case "stripe":
-		return StripeProcessor{}, nil
-	default:
-		return nil, fmt.Errorf("unsupported payment provider: %s", provider)
+	p, err := Processors.Create(provider)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported payment provider: %w", err)
+	}
+	return p, nil
+}
+
+// ConfigurableProcessor is implemented by registered processors that accept
+// a ProcessorConfig.
+type ConfigurableProcessor interface {
+	PaymentProcessor
+	WithConfig(cfg ProcessorConfig) PaymentProcessor
+}
+
+func (p PayPalProcessor) WithConfig(cfg ProcessorConfig) PaymentProcessor {
+	p.FeePercent = cfg.FeePercent
+	return p
+}
+
+func (s StripeProcessor) WithConfig(cfg ProcessorConfig) PaymentProcessor {
+	s.FeePercent = cfg.FeePercent
+	return s
+}
+
+// NewPaymentProcessorWithConfig is like NewPaymentProcessor but applies cfg
+// to the processor it returns. The registered processor must implement
+// ConfigurableProcessor.
+func NewPaymentProcessorWithConfig(provider string, cfg ProcessorConfig) (PaymentProcessor, error) {
+	if cfg.FeePercent < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNegativeFee, cfg.FeePercent)
+	}
+	p, err := NewPaymentProcessor(provider)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := p.(ConfigurableProcessor)
+	if !ok {
+		return nil, fmt.Errorf("payment provider %s does not accept a config", provider)
+	}
+	return c.WithConfig(cfg), nil
+}
+
+// ProcessAll charges every amount with p and returns one error per amount,
+// nil where the payment succeeded.
+func ProcessAll(p PaymentProcessor, amounts []float64) []error {
+	errs := make([]error, len(amounts))
+	for i, amount := range amounts {
+		errs[i] = p.ProcessPayment(amount)
+	}
+	return errs
+}
+
+// RefundPolicy decides whether a purchase can still be refunded.
+type RefundPolicy interface {
+	CanRefund(daysSincePurchase int) bool
+}
+
+type PayPalRefundPolicy struct{}
+
+func (p PayPalRefundPolicy) CanRefund(daysSincePurchase int) bool {
+	return daysSincePurchase <= 180
+}
+
+type StripeRefundPolicy struct{}
+
+func (s StripeRefundPolicy) CanRefund(daysSincePurchase int) bool {
+	return daysSincePurchase <= 120
+}
+
+// PaymentFamily is a processor together with the refund policy of the same
+// provider.
+type PaymentFamily struct {
+	Processor PaymentProcessor
+	Refunds   RefundPolicy
+}
+
+// NewPaymentFamily is an abstract factory backed by the Families registry.
+func NewPaymentFamily(provider string) (PaymentProcessor, RefundPolicy, error) {
+	family, err := Families.Create(provider)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unsupported payment provider: %w", err)
 	}
+	return family.Processor, family.Refunds, nil
 }
@@ -1,38 +1,66 @@
 package factory
 
+import (
+	"fmt"
+	"strings"
+)
+
 func Main() {
 	sendType := "email"
-	processorNotification := NewNotifer(sendType)
-	processorNotification.Send("hola")
+	processorNotification, err := NewNotifer(sendType)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if _, err := processorNotification.Send("user@example.com", "hola"); err != nil {
+		fmt.Println("Error:", err)
+	}
 }
 
 type Notifier interface {
-	Send(message string) string
+	Send(recipient, message string) (string, error)
 }
 
+var (
+	_ Notifier = (*EmailNotifier)(nil)
+	_ Notifier = (*SMSNotifier)(nil)
+	_ Notifier = (*PushNotifier)(nil)
+)
+
 type EmailNotifier struct {
 }
 
 type SMSNotifier struct {
 }
 
-func (e *EmailNotifier) Send(message string) string {
-	return "send email"
+type PushNotifier struct {
 }
 
-func (e *SMSNotifier) Send(message string) string {
-	return "send SMS"
+func (e *EmailNotifier) Send(recipient, message string) (string, error) {
+	if !strings.Contains(recipient, "@") {
+		return "", fmt.Errorf("invalid email recipient: %q", recipient)
+	}
+	return "send email", nil
 }
 
-func NewNotifer(kind string) Notifier {
-	switch kind {
-	case "email":
-		return &EmailNotifier{}
-	case "sms":
-		return &SMSNotifier{}
-	default:
-		return nil
+func (e *SMSNotifier) Send(recipient, message string) (string, error) {
+	if recipient == "" || strings.IndexFunc(recipient, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", fmt.Errorf("invalid SMS recipient: %q", recipient)
+	}
+	return "send SMS", nil
+}
 
+func (e *PushNotifier) Send(recipient, message string) (string, error) {
+	if recipient == "" {
+		return "", fmt.Errorf("invalid push recipient: %q", recipient)
 	}
+	return "send push", nil
+}
 
+func NewNotifer(kind string) (Notifier, error) {
+	n, err := Notifiers.Create(kind)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported notifier kind: %w", err)
+	}
+	return n, nil
 }
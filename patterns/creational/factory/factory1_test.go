@@ -0,0 +1,57 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewNotifer(t *testing.T) {
+	tests := []struct {
+		kind    string
+		wantErr error
+	}{
+		{"email", nil},
+		{"sms", nil},
+		{"push", nil},
+		{"pigeon", ErrNotRegistered},
+		{"", ErrNotRegistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			n, err := NewNotifer(tt.kind)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewNotifer(%q) error = %v, want %v", tt.kind, err, tt.wantErr)
+			}
+			if (n == nil) != (err != nil) {
+				t.Errorf("NewNotifer(%q) = %v, %v", tt.kind, n, err)
+			}
+		})
+	}
+}
+
+func TestNotifierSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		notifier  Notifier
+		recipient string
+		want      string
+		wantErr   bool
+	}{
+		{"email", &EmailNotifier{}, "user@example.com", "send email", false},
+		{"email without @", &EmailNotifier{}, "user.example.com", "", true},
+		{"email empty", &EmailNotifier{}, "", "", true},
+		{"sms", &SMSNotifier{}, "5551234", "send SMS", false},
+		{"sms with letters", &SMSNotifier{}, "555-CALL", "", true},
+		{"sms empty", &SMSNotifier{}, "", "", true},
+		{"push", &PushNotifier{}, "device-1", "send push", false},
+		{"push empty", &PushNotifier{}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.notifier.Send(tt.recipient, "hola")
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("Send(%q) = %q, %v; want %q, error %v", tt.recipient, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
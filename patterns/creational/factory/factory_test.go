@@ -0,0 +1,240 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout returns what f prints.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestNewPaymentProcessorWithConfig(t *testing.T) {
+	tests := []struct {
+		provider string
+		fee      float64
+		want     string
+	}{
+		{"stripe", 2.9, "[Stripe] Payment of $100.00 + $2.90 fee = $102.90 processed successfully.\n"},
+		{"paypal", 3.5, "[PayPal] Payment of $100.00 + $3.50 fee = $103.50 processed successfully.\n"},
+		{"paypal", 0, "[PayPal] Payment of $100.00 processed successfully.\n"},
+	}
+	for _, tt := range tests {
+		p, err := NewPaymentProcessorWithConfig(tt.provider, ProcessorConfig{FeePercent: tt.fee})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.provider, err)
+		}
+		got := captureStdout(t, func() {
+			if err := p.ProcessPayment(100); err != nil {
+				t.Error(err)
+			}
+		})
+		if got != tt.want {
+			t.Errorf("%s fee %v printed %q, want %q", tt.provider, tt.fee, got, tt.want)
+		}
+	}
+}
+
+// registerProcessor adds ctor to the global Processors registry for the
+// duration of the test.
+func registerProcessor(t *testing.T, name string, ctor func() PaymentProcessor) {
+	t.Helper()
+	Processors.Register(name, ctor)
+	t.Cleanup(func() { Processors.Unregister(name) })
+}
+
+// plainProcessor implements neither ContextPaymentProcessor nor
+// ConfigurableProcessor.
+type plainProcessor struct{}
+
+func (plainProcessor) ProcessPayment(float64) error { return nil }
+
+func TestNewPaymentProcessorWithConfigErrors(t *testing.T) {
+	registerProcessor(t, "plain", func() PaymentProcessor { return plainProcessor{} })
+	registerProcessor(t, "paypal-custom", func() PaymentProcessor { return PayPalProcessor{} })
+
+	tests := []struct {
+		name     string
+		provider string
+		fee      float64
+		wantErr  error
+		wantText string
+	}{
+		{"negative fee", "paypal", -1, ErrNegativeFee, ""},
+		{"unregistered", "bitcoin", 1, ErrNotRegistered, ""},
+		{"not configurable", "plain", 1, nil, "does not accept a config"},
+		{"registered later", "paypal-custom", 1, nil, ""},
+	}
+	for _, tt := range tests {
+		p, err := NewPaymentProcessorWithConfig(tt.provider, ProcessorConfig{FeePercent: tt.fee})
+		switch {
+		case tt.wantErr != nil:
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		case tt.wantText != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.wantText)
+			}
+		default:
+			if pp, ok := p.(PayPalProcessor); err != nil || !ok || pp.FeePercent != tt.fee {
+				t.Errorf("%s: got %#v, %v", tt.name, p, err)
+			}
+		}
+	}
+}
+
+func TestNewPaymentFamily(t *testing.T) {
+	tests := []struct {
+		provider   string
+		refundDays int
+		want       bool
+	}{
+		{"paypal", 150, true},
+		{"stripe", 150, false},
+		{"stripe", 120, true},
+	}
+	for _, tt := range tests {
+		_, policy, err := NewPaymentFamily(tt.provider)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.provider, err)
+		}
+		if got := policy.CanRefund(tt.refundDays); got != tt.want {
+			t.Errorf("%s CanRefund(%d) = %v, want %v", tt.provider, tt.refundDays, got, tt.want)
+		}
+	}
+	if _, _, err := NewPaymentFamily("bitcoin"); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("NewPaymentFamily(bitcoin) err = %v, want ErrNotRegistered", err)
+	}
+}
+
+func TestNewPaymentProcessor(t *testing.T) {
+	tests := []struct {
+		provider string
+		wantErr  error
+	}{
+		{"paypal", nil},
+		{"stripe", nil},
+		{"crypto", ErrNotRegistered},
+		{"", ErrNotRegistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			p, err := NewPaymentProcessor(tt.provider)
+			if !errors.Is(err, tt.wantErr) || (p == nil) != (err != nil) {
+				t.Errorf("NewPaymentProcessor(%q) = %v, %v; want error %v", tt.provider, p, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessPaymentCtx(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"live", context.Background(), nil},
+		{"canceled", canceled, context.Canceled},
+		{"deadline passed", expired, context.DeadlineExceeded},
+	}
+	for _, p := range []ContextPaymentProcessor{PayPalProcessor{}, StripeProcessor{}} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%T/%s", p, tt.name), func(t *testing.T) {
+				var err error
+				out := captureStdout(t, func() { err = p.ProcessPaymentCtx(tt.ctx, 10) })
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ProcessPaymentCtx() error = %v, want %v", err, tt.wantErr)
+				}
+				if charged := out != ""; charged != (tt.wantErr == nil) {
+					t.Errorf("charged = %v with error %v", charged, err)
+				}
+			})
+		}
+	}
+}
+
+// failOver fails every payment above limit.
+type failOver struct {
+	limit float64
+}
+
+func (f failOver) ProcessPayment(amount float64) error {
+	if amount > f.limit {
+		return fmt.Errorf("%.2f over limit", amount)
+	}
+	return nil
+}
+
+func TestProcessAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		amounts  []float64
+		wantFail []bool
+	}{
+		{"all succeed", []float64{1, 2}, []bool{false, false}},
+		{"keeps going after a failure", []float64{200, 5, 300}, []bool{true, false, true}},
+		{"empty", nil, []bool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ProcessAll(failOver{limit: 100}, tt.amounts)
+			if len(errs) != len(tt.wantFail) {
+				t.Fatalf("got %d errors, want %d", len(errs), len(tt.wantFail))
+			}
+			for i, err := range errs {
+				if (err != nil) != tt.wantFail[i] {
+					t.Errorf("errs[%d] = %v, want failure %v", i, err, tt.wantFail[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRefundPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RefundPolicy
+		days   int
+		want   bool
+	}{
+		{"paypal inside window", PayPalRefundPolicy{}, 180, true},
+		{"paypal outside window", PayPalRefundPolicy{}, 181, false},
+		{"stripe inside window", StripeRefundPolicy{}, 120, true},
+		{"stripe outside window", StripeRefundPolicy{}, 150, false},
+		{"same day", StripeRefundPolicy{}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.CanRefund(tt.days); got != tt.want {
+				t.Errorf("CanRefund(%d) = %v, want %v", tt.days, got, tt.want)
+			}
+		})
+	}
+}
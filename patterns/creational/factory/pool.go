@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ProcessorPool reuses processors created through the Processors registry,
+// with one sync.Pool per provider. As with any sync.Pool, idle instances may
+// be dropped at any time.
+//
+// Registered constructors usually return small structs by value, which a
+// sync.Pool would copy on every Get and Put. The pool therefore hands out
+// pointers, such as *PayPalProcessor, so a Get after a Put can return the
+// very instance that was put back.
+type ProcessorPool struct {
+	mu     sync.Mutex
+	pools  map[string]*sync.Pool
+	owners map[PaymentProcessor]string // instances handed out by Get -> provider
+}
+
+func NewProcessorPool() *ProcessorPool {
+	return &ProcessorPool{
+		pools:  make(map[string]*sync.Pool),
+		owners: make(map[PaymentProcessor]string),
+	}
+}
+
+// Get returns a pooled processor for provider, or nil if the provider is not
+// registered. Every processor obtained with Get should be returned with Put.
+func (pp *ProcessorPool) Get(provider string) PaymentProcessor {
+	pool := pp.poolFor(provider)
+	if pool == nil {
+		return nil
+	}
+	p := pool.Get().(PaymentProcessor)
+	pp.mu.Lock()
+	pp.owners[p] = provider
+	pp.mu.Unlock()
+	return p
+}
+
+// Put returns p to the pool of the provider it was obtained from. Processors
+// that this pool did not hand out, or that were already put back, are
+// ignored.
+func (pp *ProcessorPool) Put(p PaymentProcessor) {
+	if p == nil || !reflect.TypeOf(p).Comparable() {
+		return
+	}
+	pp.mu.Lock()
+	provider, ok := pp.owners[p]
+	delete(pp.owners, p)
+	pool := pp.pools[provider]
+	pp.mu.Unlock()
+	if ok {
+		pool.Put(p)
+	}
+}
+
+func (pp *ProcessorPool) poolFor(provider string) *sync.Pool {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	if pool, ok := pp.pools[provider]; ok {
+		return pool
+	}
+
+	first, err := Processors.Create(provider)
+	if err != nil {
+		return nil
+	}
+	pool := &sync.Pool{
+		New: func() any {
+			p, _ := Processors.Create(provider)
+			return byPointer(p)
+		},
+	}
+	pool.Put(byPointer(first))
+	pp.pools[provider] = pool
+	return pool
+}
+
+// byPointer returns p itself if it is a pointer, or else a pointer to a copy
+// of p. Methods with value receivers are also methods of the pointer type, so
+// the result is still a PaymentProcessor.
+func byPointer(p PaymentProcessor) PaymentProcessor {
+	v := reflect.ValueOf(p)
+	if v.Kind() == reflect.Pointer {
+		return p
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr.Interface().(PaymentProcessor)
+}
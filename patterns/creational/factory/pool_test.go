@@ -0,0 +1,82 @@
+package factory
+
+import "testing"
+
+func TestProcessorPoolReusesInstances(t *testing.T) {
+	pool := NewProcessorPool()
+	// sync.Pool may drop a Put, notably under the race detector, so allow a
+	// few rounds before concluding that nothing is reused.
+	for i := 0; i < 20; i++ {
+		first := pool.Get("paypal")
+		if _, ok := first.(*PayPalProcessor); !ok {
+			t.Fatalf("Get(paypal) = %#v, want *PayPalProcessor", first)
+		}
+		pool.Put(first)
+		second := pool.Get("paypal")
+		pool.Put(second)
+		if second == first {
+			return
+		}
+	}
+	t.Error("Get after Put never returned the pooled instance")
+}
+
+func TestProcessorPoolKeepsProvidersApart(t *testing.T) {
+	registerProcessor(t, "paypal-3pct", func() PaymentProcessor { return PayPalProcessor{FeePercent: 3} })
+
+	pool := NewProcessorPool()
+	for i := 0; i < 10; i++ {
+		pool.Put(pool.Get("paypal"))
+		pool.Put(pool.Get("paypal-3pct"))
+	}
+
+	tests := []struct {
+		provider string
+		wantFee  float64
+	}{
+		{"paypal", 0},
+		{"paypal-3pct", 3},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			p, ok := pool.Get(tt.provider).(*PayPalProcessor)
+			if !ok || p.FeePercent != tt.wantFee {
+				t.Fatalf("Get(%q) = %#v, want *PayPalProcessor with fee %v", tt.provider, p, tt.wantFee)
+			}
+			pool.Put(p)
+		}
+	}
+}
+
+func TestProcessorPoolPutIgnoresForeignProcessors(t *testing.T) {
+	pool := NewProcessorPool()
+	if p := pool.Get("bitcoin"); p != nil {
+		t.Errorf("Get(bitcoin) = %v, want nil", p)
+	}
+
+	owned := pool.Get("stripe")
+	tests := []struct {
+		name string
+		p    PaymentProcessor
+	}{
+		{"nil", nil},
+		{"value from the registry", StripeProcessor{}},
+		{"pointer not from Get", &StripeProcessor{}},
+		{"uncomparable", chargeLog{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool.Put(tt.p) // must not panic or reach the pool
+			if got := pool.Get("stripe"); got == tt.p && tt.p != nil {
+				t.Errorf("Get(stripe) returned the foreign processor %#v", got)
+			}
+		})
+	}
+	pool.Put(owned)
+	pool.Put(owned) // a second Put of the same instance is ignored
+}
+
+// chargeLog is a PaymentProcessor that cannot be used as a map key.
+type chargeLog []float64
+
+func (chargeLog) ProcessPayment(float64) error { return nil }
@@ -0,0 +1,112 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var ErrNotRegistered = errors.New("not registered")
+
+// Factory is a generic registry of named constructors.
+type Factory[T any] struct {
+	mu    sync.RWMutex
+	ctors map[string]func() T
+}
+
+func NewFactory[T any]() *Factory[T] {
+	return &Factory[T]{ctors: make(map[string]func() T)}
+}
+
+// Register adds or replaces the constructor for name.
+func (f *Factory[T]) Register(name string, ctor func() T) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ctors[name] = ctor
+}
+
+// Unregister removes the constructor for name, if any.
+func (f *Factory[T]) Unregister(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.ctors, name)
+}
+
+// RegisterWithValidation builds one value with ctor and registers ctor only
+// if validate accepts that value.
+func (f *Factory[T]) RegisterWithValidation(name string, ctor func() T, validate func(T) error) error {
+	if err := validate(ctor()); err != nil {
+		return fmt.Errorf("register %s: %w", name, err)
+	}
+	f.Register(name, ctor)
+	return nil
+}
+
+func (f *Factory[T]) Create(name string) (T, error) {
+	f.mu.RLock()
+	ctor, ok := f.ctors[name]
+	f.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%s (%w)", name, ErrNotRegistered)
+	}
+	return ctor(), nil
+}
+
+// AssertImplements builds one value from every constructor registered in f
+// and reports, in name order, those that do not implement the interface I.
+// It is meant for registries whose element type is wider than I, such as
+// Factory[any].
+func AssertImplements[I any, T any](f *Factory[T]) error {
+	iface := reflect.TypeFor[I]()
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("%v is not an interface type", iface)
+	}
+
+	f.mu.RLock()
+	names := make([]string, 0, len(f.ctors))
+	for name := range f.ctors {
+		names = append(names, name)
+	}
+	f.mu.RUnlock()
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		v, err := f.Create(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		t := reflect.TypeOf(v)
+		if t == nil || !t.Implements(iface) {
+			errs = append(errs, fmt.Errorf("%s: %v does not implement %v", name, t, iface))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Registries used by NewPaymentProcessor, NewPaymentFamily and NewNotifer.
+var (
+	Processors = NewFactory[PaymentProcessor]()
+	Families   = NewFactory[PaymentFamily]()
+	Notifiers  = NewFactory[Notifier]()
+)
+
+func init() {
+	Processors.Register("paypal", func() PaymentProcessor { return PayPalProcessor{} })
+	Processors.Register("stripe", func() PaymentProcessor { return StripeProcessor{} })
+
+	Families.Register("paypal", func() PaymentFamily {
+		return PaymentFamily{Processor: PayPalProcessor{}, Refunds: PayPalRefundPolicy{}}
+	})
+	Families.Register("stripe", func() PaymentFamily {
+		return PaymentFamily{Processor: StripeProcessor{}, Refunds: StripeRefundPolicy{}}
+	})
+
+	Notifiers.Register("email", func() Notifier { return &EmailNotifier{} })
+	Notifiers.Register("sms", func() Notifier { return &SMSNotifier{} })
+	Notifiers.Register("push", func() Notifier { return &PushNotifier{} })
+}
@@ -0,0 +1,151 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFactoryCreate(t *testing.T) {
+	f := NewFactory[string]()
+	f.Register("a", func() string { return "first" })
+	f.Register("a", func() string { return "replaced" })
+	f.Register("b", func() string { return "b" })
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{"a", "replaced", nil},
+		{"b", "b", nil},
+		{"missing", "", ErrNotRegistered},
+		{"", "", ErrNotRegistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Create(tt.name)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create(%q) = %q, %v; want %q, %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFactoryUnregister(t *testing.T) {
+	f := NewFactory[string]()
+	f.Register("a", func() string { return "a" })
+	f.Unregister("a")
+	f.Unregister("missing") // no-op
+
+	if _, err := f.Create("a"); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Create(a) after Unregister error = %v, want ErrNotRegistered", err)
+	}
+}
+
+func TestPackageRegistries(t *testing.T) {
+	p, err := Processors.Create("paypal")
+	if _, ok := p.(PayPalProcessor); err != nil || !ok {
+		t.Errorf(`Processors.Create("paypal") = %#v, %v`, p, err)
+	}
+	n, err := Notifiers.Create("email")
+	if _, ok := n.(*EmailNotifier); err != nil || !ok {
+		t.Errorf(`Notifiers.Create("email") = %#v, %v`, n, err)
+	}
+}
+
+func TestFactoryRegisterWithValidation(t *testing.T) {
+	errTooSmall := errors.New("too small")
+	validate := func(n int) error {
+		if n < 10 {
+			return errTooSmall
+		}
+		return nil
+	}
+	tests := []struct {
+		name    string
+		value   int
+		wantErr error
+	}{
+		{"accepted", 10, nil},
+		{"rejected", 3, errTooSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFactory[int]()
+			err := f.RegisterWithValidation(tt.name, func() int { return tt.value }, validate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RegisterWithValidation() error = %v, want %v", err, tt.wantErr)
+			}
+			_, createErr := f.Create(tt.name)
+			if registered := createErr == nil; registered != (tt.wantErr == nil) {
+				t.Errorf("registered = %v after error %v", registered, err)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "register "+tt.name+":") {
+				t.Errorf("error %q does not name the registration", err)
+			}
+		})
+	}
+}
+
+func TestFactoryConcurrent(t *testing.T) {
+	f := NewFactory[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			f.Register(fmt.Sprint(i), func() int { return i })
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			f.Create(fmt.Sprint(i))
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 20; i++ {
+		if got, err := f.Create(fmt.Sprint(i)); got != i || err != nil {
+			t.Errorf("Create(%d) = %d, %v", i, got, err)
+		}
+	}
+}
+
+func TestAssertImplements(t *testing.T) {
+	t.Run("all implement", func(t *testing.T) {
+		f := NewFactory[any]()
+		f.Register("paypal", func() any { return PayPalProcessor{} })
+		f.Register("stripe", func() any { return StripeProcessor{} })
+		if err := AssertImplements[ContextPaymentProcessor](f); err != nil {
+			t.Errorf("AssertImplements() = %v", err)
+		}
+	})
+	t.Run("reports offenders in name order", func(t *testing.T) {
+		f := NewFactory[any]()
+		f.Register("z-plain", func() any { return plainProcessor{} })
+		f.Register("a-nil", func() any { return nil })
+		f.Register("paypal", func() any { return PayPalProcessor{} })
+		err := AssertImplements[ContextPaymentProcessor](f)
+		if err == nil {
+			t.Fatal("AssertImplements() = nil, want errors")
+		}
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "a-nil:") || !strings.HasPrefix(lines[1], "z-plain:") {
+			t.Errorf("AssertImplements() = %q", err)
+		}
+	})
+	t.Run("not an interface", func(t *testing.T) {
+		if err := AssertImplements[PayPalProcessor](NewFactory[any]()); err == nil {
+			t.Error("AssertImplements with a struct type succeeded")
+		}
+	})
+	t.Run("package registries", func(t *testing.T) {
+		if err := AssertImplements[PaymentProcessor](Processors); err != nil {
+			t.Error(err)
+		}
+		if err := AssertImplements[Notifier](Notifiers); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -1,33 +1,135 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 )
 
+const defaultEnvironment = "development"
+
+var ErrConfigNotInitialized = errors.New("config not initialized: call InitConfig first")
+
 type Config struct {
-	AppName string
+	mu          sync.RWMutex
+	AppName     string
+	Environment string
 }
 
 var (
 	instance *Config
 	once     sync.Once
+
+	// initMu guards instance, loader and initErr, and is held around every
+	// once.Do so a loader is read and run in one step. loader builds the
+	// config for GetConfigE; initErr caches its failure.
+	initMu  sync.Mutex
+	loader  func() (*Config, error)
+	initErr error
 )
 
 func main() {
+	// Accessing the config before InitConfig is a programming error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("Recovered:", r) // Recovered: config not initialized: call InitConfig first
+			}
+		}()
+		GetConfig()
+	}()
+
+	InitConfig("my-app")
 	config1 := GetConfig()
 	config2 := GetConfig()
-	fmt.Println("AppName from config1:", config1.AppName)
-	fmt.Println("AppName from config2:", config2.AppName)
+	fmt.Println("AppName from config1:", config1.GetAppName())
+	fmt.Println("AppName from config2:", config2.GetAppName())
+	fmt.Println("Environment:", config1.Environment) // development
+
+	// Concurrent writers and readers share the same instance safely
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			GetConfig().SetAppName(fmt.Sprintf("app-%d", i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = GetConfig().GetAppName()
+		}()
+	}
+	wg.Wait()
+	fmt.Println("AppName after concurrent updates is set:", config1.GetAppName() != "") // true
+
+	// InitConfig already ran, so GetConfigE returns the same instance
+	config3, err := GetConfigE()
+	fmt.Println("Same instance:", config3 == config1, "error:", err) // Same instance: true error: <nil>
 }
 
-func GetConfig() *Config {
+// InitConfig creates the singleton with its defaults. Only the first call
+// has any effect.
+func InitConfig(appName string) {
+	initMu.Lock()
+	defer initMu.Unlock()
 	once.Do(func() {
-		instance = &Config{}
+		instance = &Config{
+			AppName:     appName,
+			Environment: defaultEnvironment,
+		}
 	})
+}
+
+// SetConfigLoader sets the loader that GetConfigE runs on first use. It has
+// no effect once the singleton has been initialized.
+func SetConfigLoader(l func() (*Config, error)) {
+	initMu.Lock()
+	defer initMu.Unlock()
+	loader = l
+}
+
+// GetConfigE initializes the singleton through the configured loader and
+// returns it. A failed load is cached: every later call returns the same
+// error. Without a loader it only reports ErrConfigNotInitialized, leaving
+// initialization to a later InitConfig or loader.
+func GetConfigE() (*Config, error) {
+	initMu.Lock()
+	defer initMu.Unlock()
+	if loader != nil {
+		once.Do(func() {
+			instance, initErr = loader()
+			if initErr != nil {
+				instance = nil
+			} else if instance == nil {
+				initErr = ErrConfigNotInitialized
+			}
+		})
+	}
+	if instance == nil && initErr == nil {
+		return nil, ErrConfigNotInitialized
+	}
+	return instance, initErr
+}
+
+// GetConfig returns the singleton and panics with ErrConfigNotInitialized
+// if InitConfig has not been called yet.
+func GetConfig() *Config {
+	initMu.Lock()
+	defer initMu.Unlock()
+	if instance == nil {
+		panic(ErrConfigNotInitialized)
+	}
 	return instance
 }
 
 func (c *Config) SetAppName(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.AppName = name
 }
+
+func (c *Config) GetAppName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AppName
+}
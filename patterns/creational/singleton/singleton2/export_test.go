@@ -0,0 +1,11 @@
+package main
+
+import "sync"
+
+// ResetLoggerForTest discards the current instance so the next GetLogger
+// call builds a fresh Logger. It is not safe to call while other goroutines
+// are using the logger.
+func ResetLoggerForTest() {
+	once = sync.Once{}
+	instance = nil
+}
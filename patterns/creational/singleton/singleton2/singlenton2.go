@@ -1,12 +1,47 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 	"sync"
 )
 
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+func (lv Level) String() string {
+	switch lv {
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 type Logger struct {
-	count int
+	// Output receives every logged line; nil means os.Stdout. Once the
+	// logger is shared, change it with SetOutput rather than assigning it.
+	Output io.Writer
+
+	mu       sync.Mutex
+	count    int
+	minLevel Level
 }
 
 var (
@@ -16,14 +51,80 @@ var (
 
 func GetLogger() *Logger {
 	once.Do(func() {
-		instance = &Logger{}
+		instance = &Logger{Output: os.Stdout}
 	})
 	return instance
 }
 
 func (l *Logger) Log(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	fmt.Fprintln(l.writer(), "[LOG]:", message)
+}
+
+// SetOutput redirects all log methods to w.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Output = w
+}
+
+// SetMinLevel suppresses messages below level. The default is Debug, which
+// logs everything.
+func (l *Logger) SetMinLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.minLevel = level
+}
+
+// writer must be called with l.mu held.
+func (l *Logger) writer() io.Writer {
+	if l.Output == nil {
+		return os.Stdout
+	}
+	return l.Output
+}
+
+func (l *Logger) Debug(message string) { l.logAt(Debug, message) }
+func (l *Logger) Info(message string)  { l.logAt(Info, message) }
+func (l *Logger) Warn(message string)  { l.logAt(Warn, message) }
+func (l *Logger) Error(message string) { l.logAt(Error, message) }
+
+// Logf logs msg at level followed by fields rendered as key=value pairs in
+// sorted key order. Suppressed levels produce no output.
+func (l *Logger) Logf(level Level, msg string, fields map[string]any) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	l.logAt(level, b.String())
+}
+
+// logAt prints and counts the message only when level is at least the
+// minimum set by SetMinLevel.
+func (l *Logger) logAt(level Level, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.minLevel {
+		return
+	}
 	l.count++
-	fmt.Println("[LOG]:", message)
+	fmt.Fprintf(l.writer(), "[%s]: %s\n", level, message)
+}
+
+// Count returns how many messages have been logged.
+func (l *Logger) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
 }
 
 func main() {
@@ -34,5 +135,24 @@ func main() {
 	logger2.Log("Second message")
 
 	fmt.Println("Logger instances are the same:", logger1 == logger2)
-	fmt.Println("Log count:", logger1.count)
+	fmt.Println("Log count:", logger1.Count())
+
+	// Levels below the minimum are suppressed and not counted
+	logger1.SetMinLevel(Warn)
+	logger1.Debug("cache miss")
+	logger1.Info("request served")
+	logger1.Warn("slow response")              // [WARN]: slow response
+	logger1.Error("upstream down")             // [ERROR]: upstream down
+	fmt.Println("Log count:", logger1.Count()) // 4
+
+	// Capture the output in a buffer instead of stdout
+	var buf bytes.Buffer
+	logger1.SetOutput(&buf)
+	logger1.Error("disk full")
+	fmt.Printf("Captured: %q\n", buf.String()) // Captured: "[ERROR]: disk full\n"
+
+	buf.Reset()
+	logger1.Logf(Warn, "request slow", map[string]any{"path": "/api", "ms": 950})
+	logger1.Logf(Info, "request ok", map[string]any{"path": "/health"}) // suppressed
+	fmt.Printf("Captured: %q\n", buf.String())                          // Captured: "[WARN]: request slow ms=950 path=/api\n"
 }
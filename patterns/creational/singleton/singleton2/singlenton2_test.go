@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// newTestLogger returns a fresh singleton writing into a buffer.
+func newTestLogger(t *testing.T) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	ResetLoggerForTest()
+	t.Cleanup(ResetLoggerForTest)
+	var buf bytes.Buffer
+	l := GetLogger()
+	l.SetOutput(&buf)
+	return l, &buf
+}
+
+func TestResetLoggerForTest(t *testing.T) {
+	ResetLoggerForTest()
+	t.Cleanup(ResetLoggerForTest)
+	first := GetLogger()
+	first.Log("one")
+	first.Log("two")
+
+	ResetLoggerForTest()
+	second := GetLogger()
+	second.Log("three")
+	if first == second {
+		t.Fatal("GetLogger returned the old instance after a reset")
+	}
+	if first.Count() != 2 || second.Count() != 1 {
+		t.Errorf("counts = %d, %d; want 2, 1", first.Count(), second.Count())
+	}
+}
+
+func TestLoggerMinLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		min       Level
+		wantOut   string
+		wantCount int
+	}{
+		{"default logs everything", Debug, "[DEBUG]: d\n[INFO]: i\n[WARN]: w\n[ERROR]: e\n", 4},
+		{"warn and above", Warn, "[WARN]: w\n[ERROR]: e\n", 2},
+		{"error only", Error, "[ERROR]: e\n", 1},
+		{"above every level", Error + 1, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t)
+			l.SetMinLevel(tt.min)
+			l.Debug("d")
+			l.Info("i")
+			l.Warn("w")
+			l.Error("e")
+			if got := buf.String(); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+			if got := l.Count(); got != tt.wantCount {
+				t.Errorf("Count() = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestLoggerCount(t *testing.T) {
+	ResetLoggerForTest()
+	t.Cleanup(ResetLoggerForTest)
+	l := GetLogger()
+	for i := 0; i < 3; i++ {
+		l.Log("message")
+	}
+	if got := l.Count(); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+}
+
+func TestLoggerSetOutput(t *testing.T) {
+	l, buf := newTestLogger(t)
+	l.Log("plain")
+	l.Warn("levelled")
+	if got, want := buf.String(), "[LOG]: plain\n[WARN]: levelled\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerOutputField(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Output: &buf}
+	l.Error("disk full")
+	if got, want := buf.String(), "[ERROR]: disk full\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if GetLogger().Output == nil {
+		t.Error("GetLogger().Output = nil, want os.Stdout")
+	}
+}
+
+func TestLogf(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]any
+		want   string
+	}{
+		{"no fields", nil, "[INFO]: msg\n"},
+		{"sorted keys", map[string]any{"b": 2, "a": "x"}, "[INFO]: msg a=x b=2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t)
+			l.Logf(Info, "msg", tt.fields)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerConcurrentConfig(t *testing.T) {
+	l, _ := newTestLogger(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Warn("w")
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.SetMinLevel(Level(j % 4))
+				l.SetOutput(&bytes.Buffer{})
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -6,6 +6,7 @@ import (
 )
 
 type Counter struct {
+	mu    sync.Mutex
 	value int
 }
 
@@ -13,7 +14,25 @@ func main() {
 	count := GetCounter()
 	count.Increment()
 	count.Increment()
-	fmt.Println("Counter value:", count.value)
+	fmt.Println("Counter value:", count.Value())
+
+	// Concurrent increments are safe
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			GetCounter().Increment()
+		}()
+	}
+	wg.Wait()
+	fmt.Println("Counter value after 100 goroutines:", count.Value()) // 102
+
+	// Bounded gauge: Decrement clamps at zero
+	count.Reset()
+	count.Increment()
+	fmt.Println("Decrement:", count.Decrement()) // 0
+	fmt.Println("Decrement:", count.Decrement()) // 0
 }
 
 var (
@@ -28,7 +47,31 @@ func GetCounter() *Counter {
 	return instance
 }
 func (c *Counter) Increment() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.value++
 	return c.value
 
 }
+
+// Decrement lowers the counter without going below zero and returns the new value.
+func (c *Counter) Decrement() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.value > 0 {
+		c.value--
+	}
+	return c.value
+}
+
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value = 0
+}
+
+func (c *Counter) Value() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
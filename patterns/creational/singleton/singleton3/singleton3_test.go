@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounterOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  func(c *Counter) int
+		want int
+	}{
+		{"increment", func(c *Counter) int { c.Increment(); return c.Increment() }, 2},
+		{"decrement", func(c *Counter) int { c.Increment(); c.Increment(); return c.Decrement() }, 1},
+		{"decrement clamps at zero", func(c *Counter) int { c.Decrement(); return c.Decrement() }, 0},
+		{"reset", func(c *Counter) int { c.Increment(); c.Reset(); return c.Value() }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Counter{}
+			if got := tt.ops(c); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if got := c.Value(); got != tt.want {
+				t.Errorf("Value() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	c := GetCounter()
+	c.Reset()
+	t.Cleanup(c.Reset)
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			GetCounter().Increment()
+			GetCounter().Increment()
+		}()
+		go func() {
+			defer wg.Done()
+			GetCounter().Value()
+		}()
+	}
+	wg.Wait()
+	if got := c.Value(); got != 2*n {
+		t.Errorf("Value() = %d, want %d", got, 2*n)
+	}
+
+	for i := 0; i < 3*n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Decrement()
+		}()
+	}
+	wg.Wait()
+	if got := c.Value(); got != 0 {
+		t.Errorf("Value() after extra decrements = %d, want 0", got)
+	}
+}
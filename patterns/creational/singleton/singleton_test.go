@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// resetConfig restores the package state between tests.
+func resetConfig(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		instance, once, loader, initErr = nil, sync.Once{}, nil, nil
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestGetConfigEWithoutLoaderDoesNotBlockInit(t *testing.T) {
+	resetConfig(t)
+
+	if _, err := GetConfigE(); !errors.Is(err, ErrConfigNotInitialized) {
+		t.Fatalf("GetConfigE() error = %v, want ErrConfigNotInitialized", err)
+	}
+	InitConfig("late-app")
+	if got := GetConfig().GetAppName(); got != "late-app" {
+		t.Errorf("AppName = %q, want late-app", got)
+	}
+	c, err := GetConfigE()
+	if err != nil || c != GetConfig() {
+		t.Errorf("GetConfigE() = %p, %v; want the InitConfig instance", c, err)
+	}
+}
+
+func TestGetConfigELoader(t *testing.T) {
+	errLoad := errors.New("bad file")
+	tests := []struct {
+		name    string
+		loader  func() (*Config, error)
+		wantErr error
+	}{
+		{"success", func() (*Config, error) { return &Config{AppName: "loaded"}, nil }, nil},
+		{"failure", func() (*Config, error) { return nil, errLoad }, errLoad},
+		{"nil config", func() (*Config, error) { return nil, nil }, ErrConfigNotInitialized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfig(t)
+			calls := 0
+			SetConfigLoader(func() (*Config, error) {
+				calls++
+				return tt.loader()
+			})
+			for i := 0; i < 2; i++ {
+				c, err := GetConfigE()
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetConfigE() error = %v, want %v", err, tt.wantErr)
+				}
+				if (err == nil) != (c != nil) {
+					t.Fatalf("GetConfigE() = %v, %v", c, err)
+				}
+			}
+			if calls != 1 {
+				t.Errorf("loader called %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestGetConfigPanicsBeforeInit(t *testing.T) {
+	resetConfig(t)
+	defer func() {
+		if r := recover(); r != ErrConfigNotInitialized {
+			t.Errorf("recover() = %v, want ErrConfigNotInitialized", r)
+		}
+	}()
+	GetConfig()
+}
+
+func TestConfigConcurrentAccess(t *testing.T) {
+	resetConfig(t)
+	InitConfig("app")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			GetConfig().SetAppName("renamed")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = GetConfig().GetAppName()
+		}()
+	}
+	wg.Wait()
+	if got := GetConfig().GetAppName(); got != "renamed" {
+		t.Errorf("AppName = %q, want renamed", got)
+	}
+}
+
+func TestInitConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		appName []string // InitConfig calls in order
+		want    string
+	}{
+		{"single call", []string{"app"}, "app"},
+		{"first call wins", []string{"first", "second"}, "first"},
+		{"empty name", []string{""}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfig(t)
+			for _, name := range tt.appName {
+				InitConfig(name)
+			}
+			c := GetConfig()
+			if got := c.GetAppName(); got != tt.want {
+				t.Errorf("AppName = %q, want %q", got, tt.want)
+			}
+			if c.Environment != defaultEnvironment {
+				t.Errorf("Environment = %q, want %q", c.Environment, defaultEnvironment)
+			}
+		})
+	}
+}
+
+func TestInitConfigConcurrent(t *testing.T) {
+	resetConfig(t)
+	var wg sync.WaitGroup
+	configs := make([]*Config, 20)
+	for i := range configs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			InitConfig("app")
+			configs[i] = GetConfig()
+		}(i)
+	}
+	wg.Wait()
+	for i, c := range configs {
+		if c != configs[0] {
+			t.Fatalf("goroutine %d got a different instance", i)
+		}
+	}
+}
+
+func TestGetConfigEConcurrentWithSetConfigLoader(t *testing.T) {
+	resetConfig(t)
+	var calls int32
+	load := func() (*Config, error) {
+		atomic.AddInt32(&calls, 1)
+		return &Config{AppName: "loaded"}, nil
+	}
+
+	var wg sync.WaitGroup
+	configs := make([]*Config, 20)
+	for i := range configs {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetConfigLoader(load)
+		}()
+		go func(i int) {
+			defer wg.Done()
+			configs[i], _ = GetConfigE()
+		}(i)
+	}
+	wg.Wait()
+
+	c, err := GetConfigE()
+	if err != nil || c.GetAppName() != "loaded" {
+		t.Fatalf("GetConfigE() = %v, %v; want the loaded config", c, err)
+	}
+	for i, got := range configs {
+		if got != nil && got != c {
+			t.Errorf("goroutine %d got a different instance", i)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+}
@@ -1,5 +1,7 @@
 package main
 
+import "fmt"
+
 // 1. Create a PaymentCard interface with methods:
 //    - GetAnnualFee() int
 //    - GetFeatures() string
@@ -21,40 +23,80 @@ func (b *BasiCard) GetAnnualFee() int {
 }
 
 func (b *BasiCard) GetFeatures() string {
-	return "Features: Basic Payment"
+	return "Basic Payment"
 }
 
+// 3. Create a CardDecorator struct that embeds PaymentCard
+
 type CardDecorator struct {
 	PaymentCard
 }
+
+// 4. Create decorators for card features:
+//    - Rewards (fee: +50, features: ", Cashback Rewards")
+//    - Travel (fee: +100, features: ", Travel Insurance")
+//    - Premium (fee: +200, features: ", Premium Support")
+
 type Rewards struct {
 	CardDecorator
 }
 
 func (c *Rewards) GetAnnualFee() int {
-	return 50
+	return c.PaymentCard.GetAnnualFee() + 50
 }
 func (c *Rewards) GetFeatures() string {
-	return "Features: Basic Payment"
+	return c.PaymentCard.GetFeatures() + ", Cashback Rewards"
 }
 
-func (c *CardDecorator) GetAnnualFee() int {
-	return 50
+type Travel struct {
+	CardDecorator
 }
-func (c *CardDecorator) GetFeatures() string {
-	return "Features: Basic Payment"
+
+func (c *Travel) GetAnnualFee() int {
+	return c.PaymentCard.GetAnnualFee() + 100
+}
+func (c *Travel) GetFeatures() string {
+	return c.PaymentCard.GetFeatures() + ", Travel Insurance"
 }
 
-func main() {
+type Premium struct {
+	CardDecorator
+}
 
+func (c *Premium) GetAnnualFee() int {
+	return c.PaymentCard.GetAnnualFee() + 200
+}
+func (c *Premium) GetFeatures() string {
+	return c.PaymentCard.GetFeatures() + ", Premium Support"
 }
 
-// 3. Create a CardDecorator struct that embeds PaymentCard
+// CardBuilder applies the decorators in the order they are added
+type CardBuilder struct {
+	card PaymentCard
+}
 
-// 4. Create decorators for card features:
-//    - Rewards (fee: +50, features: ", Cashback Rewards")
-//    - Travel (fee: +100, features: ", Travel Insurance")
-//    - Premium (fee: +200, features: ", Premium Support")
+func NewCardBuilder() *CardBuilder {
+	return &CardBuilder{card: &BasiCard{}}
+}
+
+func (b *CardBuilder) AddRewards() *CardBuilder {
+	b.card = &Rewards{CardDecorator{b.card}}
+	return b
+}
+
+func (b *CardBuilder) AddTravel() *CardBuilder {
+	b.card = &Travel{CardDecorator{b.card}}
+	return b
+}
+
+func (b *CardBuilder) AddPremium() *CardBuilder {
+	b.card = &Premium{CardDecorator{b.card}}
+	return b
+}
+
+func (b *CardBuilder) Build() PaymentCard {
+	return b.card
+}
 
 // 5. Test your implementation:
 //    - Start with BasicCard
@@ -62,3 +104,21 @@ func main() {
 //    - Add Travel
 //    - Add Premium
 //    - Print annual fee and features at each step
+
+func main() {
+	var card PaymentCard = &BasiCard{}
+	fmt.Println(card.GetAnnualFee(), card.GetFeatures()) // 0 Basic Payment
+
+	card = &Rewards{CardDecorator{card}}
+	fmt.Println(card.GetAnnualFee(), card.GetFeatures()) // 50 Basic Payment, Cashback Rewards
+
+	card = &Travel{CardDecorator{card}}
+	fmt.Println(card.GetAnnualFee(), card.GetFeatures()) // 150 Basic Payment, Cashback Rewards, Travel Insurance
+
+	card = &Premium{CardDecorator{card}}
+	fmt.Println(card.GetAnnualFee(), card.GetFeatures()) // 350 Basic Payment, Cashback Rewards, Travel Insurance, Premium Support
+
+	// Assemble a card with the builder
+	travelCard := NewCardBuilder().AddTravel().AddPremium().Build()
+	fmt.Println(travelCard.GetAnnualFee(), travelCard.GetFeatures()) // 300 Basic Payment, Travel Insurance, Premium Support
+}
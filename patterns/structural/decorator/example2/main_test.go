@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestCardDecorators(t *testing.T) {
+	tests := []struct {
+		name         string
+		card         PaymentCard
+		wantFee      int
+		wantFeatures string
+	}{
+		{"basic", NewCardBuilder().Build(), 0, "Basic Payment"},
+		{"rewards", NewCardBuilder().AddRewards().Build(), 50, "Basic Payment, Cashback Rewards"},
+		{"travel and premium", NewCardBuilder().AddTravel().AddPremium().Build(), 300, "Basic Payment, Travel Insurance, Premium Support"},
+		{"order follows the builder", NewCardBuilder().AddPremium().AddRewards().Build(), 250, "Basic Payment, Premium Support, Cashback Rewards"},
+		{"repeated decorator", NewCardBuilder().AddRewards().AddRewards().Build(), 100, "Basic Payment, Cashback Rewards, Cashback Rewards"},
+		{"all", NewCardBuilder().AddRewards().AddTravel().AddPremium().Build(), 350,
+			"Basic Payment, Cashback Rewards, Travel Insurance, Premium Support"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.card.GetAnnualFee(); got != tt.wantFee {
+				t.Errorf("GetAnnualFee() = %d, want %d", got, tt.wantFee)
+			}
+			if got := tt.card.GetFeatures(); got != tt.wantFeatures {
+				t.Errorf("GetFeatures() = %q, want %q", got, tt.wantFeatures)
+			}
+		})
+	}
+}
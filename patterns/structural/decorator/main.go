@@ -1,10 +1,44 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
 
 // Component - Interface base
 type Text interface {
 	Display() string
+	Accept(v Visitor)
+}
+
+// Visitor - Recorre las capas de decoradores
+type Visitor interface {
+	VisitSimple(s *SimpleText)
+	VisitBold(b *BoldDecorator)
+	VisitItalic(i *ItalicDecorator)
+	VisitUnderline(u *UnderlineDecorator)
+}
+
+// MarkerCountVisitor - Cuenta los decoradores aplicados
+type MarkerCountVisitor struct {
+	Bold      int
+	Italic    int
+	Underline int
+}
+
+func (m *MarkerCountVisitor) VisitSimple(s *SimpleText)            {}
+func (m *MarkerCountVisitor) VisitBold(b *BoldDecorator)           { m.Bold++ }
+func (m *MarkerCountVisitor) VisitItalic(i *ItalicDecorator)       { m.Italic++ }
+func (m *MarkerCountVisitor) VisitUnderline(u *UnderlineDecorator) { m.Underline++ }
+
+func (m *MarkerCountVisitor) Total() int {
+	return m.Bold + m.Italic + m.Underline
 }
 
 // Concrete Component - Implementación básica
@@ -16,6 +50,10 @@ func (s *SimpleText) Display() string {
 	return s.Content
 }
 
+func (s *SimpleText) Accept(v Visitor) {
+	v.VisitSimple(s)
+}
+
 // Decorator Base - Envuelve el componente
 type TextDecorator struct {
 	Text
@@ -30,6 +68,11 @@ func (b *BoldDecorator) Display() string {
 	return "**" + b.Text.Display() + "**"
 }
 
+func (b *BoldDecorator) Accept(v Visitor) {
+	v.VisitBold(b)
+	b.Text.Accept(v)
+}
+
 type ItalicDecorator struct {
 	TextDecorator
 }
@@ -38,6 +81,11 @@ func (i *ItalicDecorator) Display() string {
 	return "*" + i.Text.Display() + "*"
 }
 
+func (i *ItalicDecorator) Accept(v Visitor) {
+	v.VisitItalic(i)
+	i.Text.Accept(v)
+}
+
 type UnderlineDecorator struct {
 	TextDecorator
 }
@@ -46,6 +94,79 @@ func (u *UnderlineDecorator) Display() string {
 	return "__" + u.Text.Display() + "__"
 }
 
+func (u *UnderlineDecorator) Accept(v Visitor) {
+	v.VisitUnderline(u)
+	u.Text.Accept(v)
+}
+
+// RedactDecorator - Enmascara con asteriscos lo que coincida con Pattern.
+// Accept se delega al componente envuelto, así que no cuenta como marcador.
+type RedactDecorator struct {
+	TextDecorator
+	Pattern *regexp.Regexp
+}
+
+// NewRedactDecorator redacta cada una de las subcadenas literales dadas.
+func NewRedactDecorator(t Text, secrets ...string) *RedactDecorator {
+	quoted := make([]string, len(secrets))
+	for i, s := range secrets {
+		quoted[i] = regexp.QuoteMeta(s)
+	}
+	return &RedactDecorator{
+		TextDecorator: TextDecorator{t},
+		Pattern:       regexp.MustCompile(strings.Join(quoted, "|")),
+	}
+}
+
+func (r *RedactDecorator) Display() string {
+	out := r.Text.Display()
+	if r.Pattern == nil {
+		return out
+	}
+	return r.Pattern.ReplaceAllStringFunc(out, func(m string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(m))
+	})
+}
+
+// TimingDecorator - Mide cuánto tarda el Display envuelto y lo acumula.
+// Es seguro llamar a Display desde varias goroutines.
+type TimingDecorator struct {
+	TextDecorator
+	mu      sync.Mutex
+	elapsed time.Duration
+	clk     clock.Clock // nil significa clock.System
+}
+
+// SetClock reemplaza el reloj con el que se mide Display. Por defecto es
+// clock.System.
+func (t *TimingDecorator) SetClock(c clock.Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clk = c
+}
+
+func (t *TimingDecorator) Display() string {
+	t.mu.Lock()
+	clk := clock.Or(t.clk)
+	t.mu.Unlock()
+
+	start := clk.Now()
+	out := t.Text.Display()
+	d := clk.Now().Sub(start)
+
+	t.mu.Lock()
+	t.elapsed += d
+	t.mu.Unlock()
+	return out
+}
+
+// Elapsed devuelve el tiempo total de todas las llamadas a Display.
+func (t *TimingDecorator) Elapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.elapsed
+}
+
 func main() {
 	// Texto básico
 	var text Text = &SimpleText{Content: "Hello World"}
@@ -63,6 +184,25 @@ func main() {
 	text = &UnderlineDecorator{TextDecorator{text}}
 	fmt.Println("Bold + Italic + Underline:", text.Display())
 
+	// Visitor: contar los decoradores aplicados
+	counter := &MarkerCountVisitor{}
+	text.Accept(counter)
+	fmt.Println("Markers:", counter.Bold, counter.Italic, counter.Underline, "total:", counter.Total()) // Markers: 1 1 1 total: 3
+
+	// Redactar números de tarjeta sin tocar los marcadores
+	var card Text = &BoldDecorator{TextDecorator{&SimpleText{Content: "Card 4111-1111-1111-1111"}}}
+	card = &RedactDecorator{TextDecorator{card}, regexp.MustCompile(`\d{4}(-\d{4}){3}`)}
+	fmt.Println(card.Display()) // **Card *********************
+
+	secret := NewRedactDecorator(&ItalicDecorator{TextDecorator{&SimpleText{Content: "pin 1234"}}}, "1234")
+	fmt.Println(secret.Display()) // *pin *****
+
+	// Medir el tiempo de renderizado de las capas internas
+	timed := &TimingDecorator{TextDecorator: TextDecorator{text}}
+	timed.Display()
+	timed.Display()
+	fmt.Println("Rendered twice, took time:", timed.Elapsed() > 0) // Rendered twice, took time: true
+
 	var sandwich Sandwich = &BasicSandwich{}
 	fmt.Println(sandwich.GetDescription()) // Bread
 
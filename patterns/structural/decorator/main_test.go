@@ -0,0 +1,118 @@
+package main
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abrahamcorales/golang/patterns/clock"
+)
+
+// slowText is a component whose Display advances a fake clock by delay.
+type slowText struct {
+	SimpleText
+	clk   *clock.Fake
+	delay time.Duration
+}
+
+func (s *slowText) Display() string {
+	s.clk.Advance(s.delay)
+	return s.SimpleText.Display()
+}
+
+func bold(t Text) Text { return &BoldDecorator{TextDecorator{t}} }
+
+func italic(t Text) Text { return &ItalicDecorator{TextDecorator{t}} }
+
+func underline(t Text) Text { return &UnderlineDecorator{TextDecorator{t}} }
+
+func TestDecoratorsAndMarkerCount(t *testing.T) {
+	tests := []struct {
+		name                    string
+		text                    Text
+		want                    string
+		bold, italic, underline int
+	}{
+		{"plain", &SimpleText{Content: "hi"}, "hi", 0, 0, 0},
+		{"all three", underline(italic(bold(&SimpleText{Content: "hi"}))), "__***hi***__", 1, 1, 1},
+		{"repeated", bold(bold(&SimpleText{Content: "hi"})), "****hi****", 2, 0, 0},
+		{"redact is not a marker", NewRedactDecorator(italic(&SimpleText{Content: "pin 1234"}), "1234"), "*pin *****", 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.Display(); got != tt.want {
+				t.Errorf("Display() = %q, want %q", got, tt.want)
+			}
+			v := &MarkerCountVisitor{}
+			tt.text.Accept(v)
+			if v.Bold != tt.bold || v.Italic != tt.italic || v.Underline != tt.underline {
+				t.Errorf("counts = %d/%d/%d, want %d/%d/%d", v.Bold, v.Italic, v.Underline, tt.bold, tt.italic, tt.underline)
+			}
+			if want := tt.bold + tt.italic + tt.underline; v.Total() != want {
+				t.Errorf("Total() = %d, want %d", v.Total(), want)
+			}
+		})
+	}
+}
+
+func TestRedactDecorator(t *testing.T) {
+	tests := []struct {
+		name string
+		text Text
+		want string
+	}{
+		{"literal secrets", NewRedactDecorator(&SimpleText{Content: "a.b and a.b"}, "a.b"), "*** and ***"},
+		{"metacharacters are literal", NewRedactDecorator(&SimpleText{Content: "axb a.b"}, "a.b"), "axb ***"},
+		{"several secrets", NewRedactDecorator(&SimpleText{Content: "user bob pw hunter2"}, "bob", "hunter2"), "user *** pw *******"},
+		{"counts runes not bytes", NewRedactDecorator(&SimpleText{Content: "clave ñandú"}, "ñandú"), "clave *****"},
+		{"no secrets", NewRedactDecorator(&SimpleText{Content: "keep"}), "keep"},
+		{"nil pattern", &RedactDecorator{TextDecorator: TextDecorator{&SimpleText{Content: "keep"}}}, "keep"},
+		{"regexp around markers", &RedactDecorator{TextDecorator{bold(&SimpleText{Content: "Card 4111-1111-1111-1111"})},
+			regexp.MustCompile(`\d{4}(-\d{4}){3}`)}, "**Card *********************"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.Display(); got != tt.want {
+				t.Errorf("Display() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimingDecorator(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	timed := &TimingDecorator{TextDecorator: TextDecorator{bold(&slowText{SimpleText{Content: "x"}, clk, 5 * time.Millisecond})}}
+	timed.SetClock(clk)
+	if timed.Elapsed() != 0 {
+		t.Errorf("Elapsed() before Display = %v", timed.Elapsed())
+	}
+	for i := 0; i < 2; i++ {
+		if got := timed.Display(); got != "**x**" {
+			t.Errorf("Display() = %q", got)
+		}
+	}
+	if got := timed.Elapsed(); got != 10*time.Millisecond {
+		t.Errorf("Elapsed() = %v, want 10ms over two calls", got)
+	}
+}
+
+func TestTimingDecoratorConcurrentDisplay(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	timed := &TimingDecorator{TextDecorator: TextDecorator{&SimpleText{Content: "x"}}}
+	timed.SetClock(clk)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timed.Display()
+			timed.Elapsed()
+		}()
+	}
+	wg.Wait()
+	if got := timed.Elapsed(); got != 0 {
+		t.Errorf("Elapsed() = %v, want 0 with a stopped clock", got)
+	}
+}